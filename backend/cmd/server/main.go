@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -9,6 +10,7 @@ import (
 
 	"expensio-backend/internal/config"
 	"expensio-backend/internal/routes"
+	"expensio-backend/internal/worker"
 	"expensio-backend/pkg/cache"
 	"expensio-backend/pkg/database"
 
@@ -67,7 +69,14 @@ func main() {
 	})
 
 	// Setup routes
-	routes.SetupRoutes(app, cfg)
+	services := routes.SetupRoutes(app, cfg)
+
+	// Start background workers, stopped on shutdown
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	worker.NewEscalationWorker(services.Approval, cfg).Start(workerCtx)
+	worker.NewDigestWorker(services.Digest, cfg).Start(workerCtx)
+	worker.NewDraftWorker(services.Expense, cfg).Start(workerCtx)
 
 	// Create upload directories if they don't exist
 	createDirectories(cfg)
@@ -78,6 +87,7 @@ func main() {
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 		<-sigChan
 		log.Println("🛑 Shutting down gracefully...")
+		stopWorkers()
 		app.Shutdown()
 	}()
 
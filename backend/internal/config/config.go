@@ -18,6 +18,12 @@ type Config struct {
 	OCR          OCRConfig
 	Cache        CacheConfig
 	FileUpload   FileUploadConfig
+	Escalation   EscalationConfig
+	ActionLink   ActionLinkConfig
+	SMTP         SMTPConfig
+	Digest       DigestConfig
+	Pagination   PaginationConfig
+	Drafts       DraftConfig
 }
 
 type ServerConfig struct {
@@ -68,6 +74,44 @@ type FileUploadConfig struct {
 	UploadDir   string
 }
 
+type EscalationConfig struct {
+	Enabled         bool
+	Interval        time.Duration
+	DefaultSLAHours int
+	WorkdayStart    int // Hour of day business hours start
+	WorkdayEnd      int // Hour of day business hours end
+	Timezone        string
+}
+
+type ActionLinkConfig struct {
+	Secret  string
+	Expiry  time.Duration
+	BaseURL string // Public URL the signed approve and reject links point to
+}
+
+type SMTPConfig struct {
+	Host     string // Empty logs notifications instead of mailing them
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type DigestConfig struct {
+	Enabled         bool
+	Interval        time.Duration // How often due digests are looked for
+	DefaultSendTime string        // Local "HH:MM" for users who did not choose one
+}
+
+type PaginationConfig struct {
+	CursorSecret string // Signs list cursors so clients cannot forge positions
+}
+
+type DraftConfig struct {
+	StaleAfter    time.Duration // Age after which the owner is reminded of a draft
+	CheckInterval time.Duration
+}
+
 var AppConfig *Config
 
 // LoadConfig loads configuration from environment variables
@@ -118,6 +162,38 @@ func LoadConfig() *Config {
 			MaxFileSize: int64(getEnvAsInt("MAX_FILE_SIZE", 10485760)), // 10MB default
 			UploadDir:   getEnv("UPLOAD_DIR", "./uploads"),
 		},
+		Escalation: EscalationConfig{
+			Enabled:         getEnvAsBool("ESCALATION_ENABLED", true),
+			Interval:        parseDuration(getEnv("ESCALATION_INTERVAL", "15m")),
+			DefaultSLAHours: getEnvAsInt("ESCALATION_DEFAULT_SLA_HOURS", 16), // Two working days
+			WorkdayStart:    getEnvAsInt("ESCALATION_WORKDAY_START", 9),
+			WorkdayEnd:      getEnvAsInt("ESCALATION_WORKDAY_END", 17),
+			Timezone:        getEnv("ESCALATION_TIMEZONE", "UTC"),
+		},
+		ActionLink: ActionLinkConfig{
+			Secret:  getEnv("ACTION_LINK_SECRET", "your-action-link-secret-change-in-production"),
+			Expiry:  parseDuration(getEnv("ACTION_LINK_EXPIRY", "72h")),
+			BaseURL: getEnv("ACTION_LINK_BASE_URL", "http://localhost:8080/api/v1/action-links"),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnv("SMTP_PORT", "1025"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "Expensio <no-reply@expensio.local>"),
+		},
+		Digest: DigestConfig{
+			Enabled:         getEnvAsBool("DIGEST_ENABLED", true),
+			Interval:        parseDuration(getEnv("DIGEST_INTERVAL", "5m")),
+			DefaultSendTime: getEnv("DIGEST_DEFAULT_SEND_TIME", "08:00"),
+		},
+		Pagination: PaginationConfig{
+			CursorSecret: getEnv("CURSOR_SECRET", "your-cursor-secret-change-in-production"),
+		},
+		Drafts: DraftConfig{
+			StaleAfter:    parseDuration(getEnv("DRAFT_STALE_AFTER", "168h")), // 7 days
+			CheckInterval: parseDuration(getEnv("DRAFT_CHECK_INTERVAL", "1h")),
+		},
 	}
 
 	AppConfig = config
@@ -141,6 +217,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsBool retrieves environment variable as boolean or returns default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 // parseDuration parses duration string (e.g., "15m", "7d")
 func parseDuration(s string) time.Duration {
 	duration, err := time.ParseDuration(s)
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,17 +18,28 @@ const (
 
 // User represents a user in the system
 type User struct {
-	ID        primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
-	Email     string              `json:"email" bson:"email"`
-	Password  string              `json:"-" bson:"password"` // Never expose in JSON
-	FirstName string              `json:"first_name" bson:"first_name"`
-	LastName  string              `json:"last_name" bson:"last_name"`
-	Role      UserRole            `json:"role" bson:"role"`
-	CompanyID primitive.ObjectID  `json:"company_id" bson:"company_id"`
-	ManagerID *primitive.ObjectID `json:"manager_id,omitempty" bson:"manager_id,omitempty"` // For employees
-	IsActive  bool                `json:"is_active" bson:"is_active"`
-	CreatedAt time.Time           `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time           `json:"updated_at" bson:"updated_at"`
+	ID            primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Email         string              `json:"email" bson:"email"`
+	Password      string              `json:"-" bson:"password"` // Never expose in JSON
+	FirstName     string              `json:"first_name" bson:"first_name"`
+	LastName      string              `json:"last_name" bson:"last_name"`
+	Role          UserRole            `json:"role" bson:"role"`
+	CompanyID     primitive.ObjectID  `json:"company_id" bson:"company_id"`
+	ManagerID     *primitive.ObjectID `json:"manager_id,omitempty" bson:"manager_id,omitempty"`         // For employees
+	ApprovalLimit *float64            `json:"approval_limit,omitempty" bson:"approval_limit,omitempty"` // Signing limit in company base currency, nil means unlimited
+	Department    string              `json:"department,omitempty" bson:"department,omitempty"`
+	Digest        *DigestSettings     `json:"digest,omitempty" bson:"digest,omitempty"` // Nil means the user never opted in
+	IsActive      bool                `json:"is_active" bson:"is_active"`
+	CreatedAt     time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// DigestSettings is a user's choice about the daily digest of pending approvals
+type DigestSettings struct {
+	Enabled    bool   `json:"enabled" bson:"enabled"`
+	SendTime   string `json:"send_time" bson:"send_time"`                           // "HH:MM" in Timezone
+	Timezone   string `json:"timezone" bson:"timezone"`                             // IANA name, e.g. Europe/Berlin
+	LastSentOn string `json:"last_sent_on,omitempty" bson:"last_sent_on,omitempty"` // Local date of the last digest, keeps it daily
 }
 
 // Company represents a company/organization
@@ -38,18 +50,50 @@ type Company struct {
 	Country        string              `json:"country" bson:"country"`
 	AdminUserID    primitive.ObjectID  `json:"admin_user_id" bson:"admin_user_id"`
 	ApprovalRuleID *primitive.ObjectID `json:"approval_rule_id,omitempty" bson:"approval_rule_id,omitempty"`
+	Settings       CompanySettings     `json:"settings" bson:"settings"`
 	IsActive       bool                `json:"is_active" bson:"is_active"`
 	CreatedAt      time.Time           `json:"created_at" bson:"created_at"`
 	UpdatedAt      time.Time           `json:"updated_at" bson:"updated_at"`
 }
 
+// CompanySettings holds company-wide workflow settings
+type CompanySettings struct {
+	ApprovalSLAHours   int                 `json:"approval_sla_hours" bson:"approval_sla_hours"` // Business hours before a pending approval is escalated, 0 uses the server default
+	SelfApprovalPolicy SelfApprovalPolicy  `json:"self_approval_policy" bson:"self_approval_policy,omitempty"`
+	NoApproverPolicy   NoApproverPolicy    `json:"no_approver_policy" bson:"no_approver_policy,omitempty"`
+	FallbackApproverID *primitive.ObjectID `json:"fallback_approver_id,omitempty" bson:"fallback_approver_id,omitempty"` // Used by the fallback_approver policy
+}
+
+// SelfApprovalPolicy defines how a submitter listed among their own approvers is handled
+type SelfApprovalPolicy string
+
+const (
+	SelfApprovalSkip       SelfApprovalPolicy = "skip"       // Drop the submitter from the chain
+	SelfApprovalSubstitute SelfApprovalPolicy = "substitute" // Ask the submitter's manager, or the company admin, instead
+)
+
+// NoApproverPolicy defines what happens when nobody but the submitter could approve an expense
+type NoApproverPolicy string
+
+const (
+	NoApproverAutoApprove NoApproverPolicy = "auto_approve"      // Approve the expense automatically
+	NoApproverFallback    NoApproverPolicy = "fallback_approver" // Ask the company's fallback approver
+	NoApproverReject      NoApproverPolicy = "reject"            // Reject the expense
+)
+
 // ExpenseStatus defines expense statuses
 type ExpenseStatus string
 
 const (
+	StatusDraft    ExpenseStatus = "draft" // Saved by its owner, not submitted for approval yet
 	StatusPending  ExpenseStatus = "pending"
 	StatusApproved ExpenseStatus = "approved"
 	StatusRejected ExpenseStatus = "rejected"
+
+	StatusChangesRequested ExpenseStatus = "changes_requested" // Sent back to the submitter for corrections
+	StatusWithdrawn        ExpenseStatus = "withdrawn"         // Taken out of approval by the submitter
+	StatusScheduled        ExpenseStatus = "scheduled"         // Approved and added to a reimbursement batch
+	StatusPaid             ExpenseStatus = "paid"              // Reimbursed, the expense can no longer change
 )
 
 // ExpenseCategory defines expense categories
@@ -64,83 +108,126 @@ const (
 	CategoryOther         ExpenseCategory = "other"
 )
 
+// ExpenseLineItem is one line of an itemized expense, e.g. the room of a hotel
+// bill, or one category allocation of a split expense
+type ExpenseLineItem struct {
+	Amount          float64         `json:"amount" bson:"amount"` // In the expense currency, tax included
+	Category        ExpenseCategory `json:"category" bson:"category"`
+	Tax             float64         `json:"tax" bson:"tax"`
+	Description     string          `json:"description,omitempty" bson:"description,omitempty"`
+	ConvertedAmount float64         `json:"converted_amount" bson:"converted_amount"` // In company's base currency
+}
+
 // Expense represents an expense claim
 type Expense struct {
-	ID                   primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	UserID               primitive.ObjectID `json:"user_id" bson:"user_id"`
-	CompanyID            primitive.ObjectID `json:"company_id" bson:"company_id"`
-	Amount               float64            `json:"amount" bson:"amount"`
-	Currency             string             `json:"currency" bson:"currency"`
-	ConvertedAmount      float64            `json:"converted_amount" bson:"converted_amount"` // In company's base currency
-	ExchangeRate         float64            `json:"exchange_rate" bson:"exchange_rate"`
-	Category             ExpenseCategory    `json:"category" bson:"category"`
-	Description          string             `json:"description" bson:"description"`
-	ExpenseDate          time.Time          `json:"expense_date" bson:"expense_date"`
-	ReceiptURL           string             `json:"receipt_url,omitempty" bson:"receipt_url,omitempty"`
-	Merchant             string             `json:"merchant,omitempty" bson:"merchant,omitempty"`
-	Status               ExpenseStatus      `json:"status" bson:"status"`
-	CurrentApprovalLevel int                `json:"current_approval_level" bson:"current_approval_level"`
-	CreatedAt            time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt            time.Time          `json:"updated_at" bson:"updated_at"`
+	ID                   primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID               primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	CompanyID            primitive.ObjectID  `json:"company_id" bson:"company_id"`
+	Amount               float64             `json:"amount" bson:"amount"`
+	Currency             string              `json:"currency" bson:"currency"`
+	ConvertedAmount      float64             `json:"converted_amount" bson:"converted_amount"` // In company's base currency
+	ExchangeRate         float64             `json:"exchange_rate" bson:"exchange_rate"`
+	Category             ExpenseCategory     `json:"category" bson:"category"` // Category of the largest line when itemized
+	Description          string              `json:"description" bson:"description"`
+	ExpenseDate          time.Time           `json:"expense_date" bson:"expense_date"`
+	ReceiptURL           string              `json:"receipt_url,omitempty" bson:"receipt_url,omitempty"`
+	Merchant             string              `json:"merchant,omitempty" bson:"merchant,omitempty"`
+	LineItems            []ExpenseLineItem   `json:"line_items,omitempty" bson:"line_items"` // Optional, the lines sum to Amount
+	Status               ExpenseStatus       `json:"status" bson:"status"`
+	CurrentApprovalLevel int                 `json:"current_approval_level" bson:"current_approval_level"`
+	ApprovalThreshold    *AmountThreshold    `json:"approval_threshold,omitempty" bson:"approval_threshold,omitempty"` // Amount band chosen when approvals were initialized
+	ApprovalRuleID       *primitive.ObjectID `json:"approval_rule_id,omitempty" bson:"approval_rule_id,omitempty"`     // Rule selected for the expense, also when a fallback route applies
+	ApprovalRule         *ApprovalRule       `json:"approval_rule,omitempty" bson:"approval_rule,omitempty"`           // Rule the expense was routed under, frozen at initialization
+	ApprovalRound        int                 `json:"approval_round" bson:"approval_round"`                             // Incremented each time the expense is re-routed
+	Version              int                 `json:"version" bson:"version"`                                           // Incremented on every write, guards concurrent updates
+	ReimbursementBatchID *primitive.ObjectID `json:"reimbursement_batch_id,omitempty" bson:"reimbursement_batch_id,omitempty"`
+	DraftFlaggedAt       *time.Time          `json:"draft_flagged_at,omitempty" bson:"draft_flagged_at,omitempty"` // When the owner was reminded of the stale draft
+	ReportID             *primitive.ObjectID `json:"report_id,omitempty" bson:"report_id,omitempty"`               // Expense report the expense is part of
+	RoutedReportID       *primitive.ObjectID `json:"routed_report_id,omitempty" bson:"routed_report_id,omitempty"` // Set on the expense that carries a whole report through approval
+	CreatedAt            time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt            time.Time           `json:"updated_at" bson:"updated_at"`
 }
 
 // ApprovalStatus defines approval statuses
 type ApprovalStatus string
 
 const (
-	ApprovalPending  ApprovalStatus = "pending"
-	ApprovalApproved ApprovalStatus = "approved"
-	ApprovalRejected ApprovalStatus = "rejected"
+	ApprovalPending   ApprovalStatus = "pending"
+	ApprovalQueued    ApprovalStatus = "queued" // Sequential level not reached yet
+	ApprovalApproved  ApprovalStatus = "approved"
+	ApprovalRejected  ApprovalStatus = "rejected"
+	ApprovalEscalated ApprovalStatus = "escalated" // Handed over to another approver after the SLA expired
+	ApprovalCancelled ApprovalStatus = "cancelled" // Superseded when the expense was re-routed or withdrawn
+
+	ApprovalChangesRequested ApprovalStatus = "changes_requested" // Approver sent the expense back to the submitter
 )
 
 // Approval represents an individual approval action
 type Approval struct {
-	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	ExpenseID  primitive.ObjectID `json:"expense_id" bson:"expense_id"`
-	ApproverID primitive.ObjectID `json:"approver_id" bson:"approver_id"`
-	Level      int                `json:"level" bson:"level"` // Approval level in sequence
-	Status     ApprovalStatus     `json:"status" bson:"status"`
-	Comments   string             `json:"comments,omitempty" bson:"comments,omitempty"`
-	ApprovedAt *time.Time         `json:"approved_at,omitempty" bson:"approved_at,omitempty"`
-	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
+	ID               primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	ExpenseID        primitive.ObjectID  `json:"expense_id" bson:"expense_id"`
+	ApproverID       primitive.ObjectID  `json:"approver_id" bson:"approver_id"`
+	OnBehalfOfID     *primitive.ObjectID `json:"on_behalf_of_id,omitempty" bson:"on_behalf_of_id,omitempty"` // Original approver when acting as a delegate
+	DelegationID     *primitive.ObjectID `json:"delegation_id,omitempty" bson:"delegation_id,omitempty"`
+	Level            int                 `json:"level" bson:"level"` // Approval level in sequence
+	Round            int                 `json:"round" bson:"round"` // Approval round of the expense this approval belongs to
+	Status           ApprovalStatus      `json:"status" bson:"status"`
+	Comments         string              `json:"comments,omitempty" bson:"comments,omitempty"`
+	ApprovedAt       *time.Time          `json:"approved_at,omitempty" bson:"approved_at,omitempty"`
+	AssignedAt       *time.Time          `json:"assigned_at,omitempty" bson:"assigned_at,omitempty"` // When the approval became pending, starts the SLA clock
+	EscalatedAt      *time.Time          `json:"escalated_at,omitempty" bson:"escalated_at,omitempty"`
+	EscalatedToID    *primitive.ObjectID `json:"escalated_to_id,omitempty" bson:"escalated_to_id,omitempty"`     // Approver who took over after escalation
+	EscalatedFromID  *primitive.ObjectID `json:"escalated_from_id,omitempty" bson:"escalated_from_id,omitempty"` // Escalated approval this one replaces
+	EscalationReason string              `json:"escalation_reason,omitempty" bson:"escalation_reason,omitempty"` // Why the approval was handed over
+	CreatedAt        time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at" bson:"updated_at"`
 }
 
 // ApprovalWithDetails extends Approval with populated expense and user data
 // Used for API responses where related data needs to be included
 type ApprovalWithDetails struct {
-	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	ExpenseID  primitive.ObjectID `json:"expense_id" bson:"expense_id"`
-	ApproverID primitive.ObjectID `json:"approver_id" bson:"approver_id"`
-	Level      int                `json:"level" bson:"level"`
-	Status     ApprovalStatus     `json:"status" bson:"status"`
-	Comments   string             `json:"comments,omitempty" bson:"comments,omitempty"`
-	ApprovedAt *time.Time         `json:"approved_at,omitempty" bson:"approved_at,omitempty"`
-	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
-	Expense    *ExpenseWithUser   `json:"expense,omitempty" bson:"expense,omitempty"`
-	Approver   *User              `json:"approver,omitempty" bson:"approver,omitempty"`
+	ID               primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	ExpenseID        primitive.ObjectID  `json:"expense_id" bson:"expense_id"`
+	ApproverID       primitive.ObjectID  `json:"approver_id" bson:"approver_id"`
+	OnBehalfOfID     *primitive.ObjectID `json:"on_behalf_of_id,omitempty" bson:"on_behalf_of_id,omitempty"`
+	DelegationID     *primitive.ObjectID `json:"delegation_id,omitempty" bson:"delegation_id,omitempty"`
+	Level            int                 `json:"level" bson:"level"`
+	Round            int                 `json:"round" bson:"round"`
+	Status           ApprovalStatus      `json:"status" bson:"status"`
+	Comments         string              `json:"comments,omitempty" bson:"comments,omitempty"`
+	ApprovedAt       *time.Time          `json:"approved_at,omitempty" bson:"approved_at,omitempty"`
+	AssignedAt       *time.Time          `json:"assigned_at,omitempty" bson:"assigned_at,omitempty"` // When the approval became pending, starts the SLA clock
+	EscalatedAt      *time.Time          `json:"escalated_at,omitempty" bson:"escalated_at,omitempty"`
+	EscalatedToID    *primitive.ObjectID `json:"escalated_to_id,omitempty" bson:"escalated_to_id,omitempty"`     // Approver who took over after escalation
+	EscalatedFromID  *primitive.ObjectID `json:"escalated_from_id,omitempty" bson:"escalated_from_id,omitempty"` // Escalated approval this one replaces
+	EscalationReason string              `json:"escalation_reason,omitempty" bson:"escalation_reason,omitempty"` // Why the approval was handed over
+	CreatedAt        time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at" bson:"updated_at"`
+	Expense          *ExpenseWithUser    `json:"expense,omitempty" bson:"expense,omitempty"`
+	Approver         *User               `json:"approver,omitempty" bson:"approver,omitempty"`
 }
 
 // ExpenseWithUser extends Expense with populated user data
 type ExpenseWithUser struct {
-	ID                   primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	UserID               primitive.ObjectID `json:"user_id" bson:"user_id"`
-	CompanyID            primitive.ObjectID `json:"company_id" bson:"company_id"`
-	Amount               float64            `json:"amount" bson:"amount"`
-	Currency             string             `json:"currency" bson:"currency"`
-	ConvertedAmount      float64            `json:"converted_amount" bson:"converted_amount"`
-	ExchangeRate         float64            `json:"exchange_rate" bson:"exchange_rate"`
-	Category             ExpenseCategory    `json:"category" bson:"category"`
-	Description          string             `json:"description" bson:"description"`
-	ExpenseDate          time.Time          `json:"expense_date" bson:"expense_date"`
-	ReceiptURL           string             `json:"receipt_url,omitempty" bson:"receipt_url,omitempty"`
-	Merchant             string             `json:"merchant,omitempty" bson:"merchant,omitempty"`
-	Status               ExpenseStatus      `json:"status" bson:"status"`
-	CurrentApprovalLevel int                `json:"current_approval_level" bson:"current_approval_level"`
-	CreatedAt            time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt            time.Time          `json:"updated_at" bson:"updated_at"`
-	User                 *User              `json:"user,omitempty" bson:"user,omitempty"`
+	ID                   primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UserID               primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	CompanyID            primitive.ObjectID  `json:"company_id" bson:"company_id"`
+	Amount               float64             `json:"amount" bson:"amount"`
+	Currency             string              `json:"currency" bson:"currency"`
+	ConvertedAmount      float64             `json:"converted_amount" bson:"converted_amount"`
+	ExchangeRate         float64             `json:"exchange_rate" bson:"exchange_rate"`
+	Category             ExpenseCategory     `json:"category" bson:"category"`
+	Description          string              `json:"description" bson:"description"`
+	ExpenseDate          time.Time           `json:"expense_date" bson:"expense_date"`
+	ReceiptURL           string              `json:"receipt_url,omitempty" bson:"receipt_url,omitempty"`
+	Merchant             string              `json:"merchant,omitempty" bson:"merchant,omitempty"`
+	LineItems            []ExpenseLineItem   `json:"line_items,omitempty" bson:"line_items"`
+	Status               ExpenseStatus       `json:"status" bson:"status"`
+	CurrentApprovalLevel int                 `json:"current_approval_level" bson:"current_approval_level"`
+	RoutedReportID       *primitive.ObjectID `json:"routed_report_id,omitempty" bson:"routed_report_id,omitempty"`
+	CreatedAt            time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt            time.Time           `json:"updated_at" bson:"updated_at"`
+	User                 *User               `json:"user,omitempty" bson:"user,omitempty"`
 }
 
 // ApprovalRuleType defines types of approval rules
@@ -151,6 +238,7 @@ const (
 	RuleTypePercentage       ApprovalRuleType = "percentage"        // X% approval required
 	RuleTypeSpecificApprover ApprovalRuleType = "specific_approver" // Specific person approval
 	RuleTypeHybrid           ApprovalRuleType = "hybrid"            // Combination of rules
+	RuleTypeManagerChain     ApprovalRuleType = "manager_chain"     // Walk up the submitter's managers
 )
 
 // ApprovalRule defines approval workflow rules for a company
@@ -159,6 +247,10 @@ type ApprovalRule struct {
 	CompanyID           primitive.ObjectID   `json:"company_id" bson:"company_id"`
 	Name                string               `json:"name" bson:"name"`
 	Type                ApprovalRuleType     `json:"type" bson:"type"`
+	Version             int                  `json:"version" bson:"version"`                       // Incremented on every update
+	Priority            int                  `json:"priority" bson:"priority"`                     // Lower priorities are matched first
+	Criteria            *RuleCriteria        `json:"criteria,omitempty" bson:"criteria,omitempty"` // Expenses the rule applies to, nil matches all
+	IsDefault           bool                 `json:"is_default" bson:"is_default"`                 // Company fallback when no other active rule matches
 	SequentialApprovers []primitive.ObjectID `json:"sequential_approvers,omitempty" bson:"sequential_approvers,omitempty"`
 	PercentageRequired  *float64             `json:"percentage_required,omitempty" bson:"percentage_required,omitempty"` // e.g., 60.0 for 60%
 	SpecificApproverID  *primitive.ObjectID  `json:"specific_approver_id,omitempty" bson:"specific_approver_id,omitempty"`
@@ -166,18 +258,218 @@ type ApprovalRule struct {
 	MaximumApprovals    int                  `json:"maximum_approvals" bson:"maximum_approvals"`
 	AllowedApprovers    []primitive.ObjectID `json:"allowed_approvers,omitempty" bson:"allowed_approvers,omitempty"`
 	AmountThresholds    []AmountThreshold    `json:"amount_thresholds,omitempty" bson:"amount_thresholds,omitempty"`
+	Expression          *ApprovalExpression  `json:"expression,omitempty" bson:"expression,omitempty"`       // Hybrid rules only
+	ManagerChain        *ManagerChain        `json:"manager_chain,omitempty" bson:"manager_chain,omitempty"` // Manager chain rules only
+	ResubmissionPolicy  ResubmissionPolicy   `json:"resubmission_policy" bson:"resubmission_policy"`
 	IsActive            bool                 `json:"is_active" bson:"is_active"`
 	CreatedAt           time.Time            `json:"created_at" bson:"created_at"`
 	UpdatedAt           time.Time            `json:"updated_at" bson:"updated_at"`
 }
 
-// AmountThreshold defines different approval rules based on expense amount
+// RuleCriteria restricts the expenses an approval rule applies to. Empty
+// fields do not restrict; set fields must all match.
+type RuleCriteria struct {
+	Categories     []ExpenseCategory `json:"categories,omitempty" bson:"categories,omitempty"`
+	MinAmount      *float64          `json:"min_amount,omitempty" bson:"min_amount,omitempty"` // Inclusive, in company base currency
+	MaxAmount      *float64          `json:"max_amount,omitempty" bson:"max_amount,omitempty"` // Exclusive, in company base currency
+	SubmitterRoles []UserRole        `json:"submitter_roles,omitempty" bson:"submitter_roles,omitempty"`
+	Departments    []string          `json:"departments,omitempty" bson:"departments,omitempty"`
+	Currencies     []string          `json:"currencies,omitempty" bson:"currencies,omitempty"` // Currency the expense was submitted in
+}
+
+// ManagerChain configures how far a manager_chain rule walks up the hierarchy
+type ManagerChain struct {
+	Levels     int  `json:"levels,omitempty" bson:"levels,omitempty"` // Managers to ask; with UntilLimit the most to walk, 0 for no bound
+	UntilLimit bool `json:"until_limit" bson:"until_limit"`           // Stop at the first manager whose approval limit covers the amount
+}
+
+// ResubmissionPolicy defines how approvals continue after the submitter
+// resubmits an expense that was sent back for changes
+type ResubmissionPolicy string
+
+const (
+	ResubmissionRestart ResubmissionPolicy = "restart" // Route the expense again from the first level
+	ResubmissionResume  ResubmissionPolicy = "resume"  // Ask only the approver who requested changes again
+)
+
+// ExpressionOperator identifies a node of an approval expression
+type ExpressionOperator string
+
+const (
+	ExprAnd              ExpressionOperator = "and"
+	ExprOr               ExpressionOperator = "or"
+	ExprSpecificApprover ExpressionOperator = "specific_approver" // ApproverID approved
+	ExprCountOf          ExpressionOperator = "count_of"          // At least Count of Approvers approved
+	ExprPercentageOf     ExpressionOperator = "percentage_of"     // At least Percentage of Approvers approved
+	ExprDirectManager    ExpressionOperator = "direct_manager"    // The submitter's manager approved
+	ExprAmountAbove      ExpressionOperator = "amount_above"      // Converted amount is greater than Amount
+)
+
+// ApprovalExpression is a boolean expression tree deciding a hybrid rule.
+// "and"/"or" nodes combine Conditions, every other operator is a leaf.
+type ApprovalExpression struct {
+	Operator   ExpressionOperator   `json:"operator" bson:"operator"`
+	Conditions []ApprovalExpression `json:"conditions,omitempty" bson:"conditions,omitempty"`
+	ApproverID *primitive.ObjectID  `json:"approver_id,omitempty" bson:"approver_id,omitempty"`
+	Approvers  []primitive.ObjectID `json:"approvers,omitempty" bson:"approvers,omitempty"`
+	Count      int                  `json:"count,omitempty" bson:"count,omitempty"`
+	Percentage float64              `json:"percentage,omitempty" bson:"percentage,omitempty"`
+	Amount     float64              `json:"amount,omitempty" bson:"amount,omitempty"` // In company base currency
+}
+
+// AmountThreshold defines different approval rules based on expense amount.
+// A band covers [MinAmount, MaxAmount) of the converted amount in the company
+// base currency; MaxAmount 0 means the band has no upper bound.
 type AmountThreshold struct {
 	MinAmount         float64              `json:"min_amount" bson:"min_amount"`
 	MaxAmount         float64              `json:"max_amount" bson:"max_amount"`
 	RequiredApprovers []primitive.ObjectID `json:"required_approvers" bson:"required_approvers"`
 }
 
+// Delegation routes a user's approvals to a substitute for a period of time
+type Delegation struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CompanyID   primitive.ObjectID `json:"company_id" bson:"company_id"`
+	DelegatorID primitive.ObjectID `json:"delegator_id" bson:"delegator_id"` // Approver who is away
+	DelegateID  primitive.ObjectID `json:"delegate_id" bson:"delegate_id"`   // Substitute approver
+	StartDate   time.Time          `json:"start_date" bson:"start_date"`
+	EndDate     time.Time          `json:"end_date" bson:"end_date"`
+	Categories  []ExpenseCategory  `json:"categories,omitempty" bson:"categories,omitempty"` // Empty means all categories
+	MaxAmount   *float64           `json:"max_amount,omitempty" bson:"max_amount,omitempty"` // In company base currency, nil means any amount
+	Reason      string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	IsActive    bool               `json:"is_active" bson:"is_active"` // False once revoked
+	CreatedBy   primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// BatchStatus defines reimbursement batch statuses
+type BatchStatus string
+
+const (
+	BatchScheduled BatchStatus = "scheduled"
+	BatchPaid      BatchStatus = "paid"
+)
+
+// ReimbursementBatch groups approved expenses of one employee in one currency
+// that are paid out together
+type ReimbursementBatch struct {
+	ID               primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	CompanyID        primitive.ObjectID   `json:"company_id" bson:"company_id"`
+	UserID           primitive.ObjectID   `json:"user_id" bson:"user_id"` // Employee being reimbursed
+	Currency         string               `json:"currency" bson:"currency"`
+	ExpenseIDs       []primitive.ObjectID `json:"expense_ids" bson:"expense_ids"`
+	TotalAmount      float64              `json:"total_amount" bson:"total_amount"` // In the batch currency
+	Status           BatchStatus          `json:"status" bson:"status"`
+	PaymentDate      *time.Time           `json:"payment_date,omitempty" bson:"payment_date,omitempty"`
+	PaymentReference string               `json:"payment_reference,omitempty" bson:"payment_reference,omitempty"`
+	CreatedBy        primitive.ObjectID   `json:"created_by" bson:"created_by"`
+	PaidBy           *primitive.ObjectID  `json:"paid_by,omitempty" bson:"paid_by,omitempty"`
+	CreatedAt        time.Time            `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at" bson:"updated_at"`
+}
+
+// ReportStatus defines expense report statuses
+type ReportStatus string
+
+const (
+	ReportDraft            ReportStatus = "draft"
+	ReportPending          ReportStatus = "pending"
+	ReportApproved         ReportStatus = "approved"
+	ReportRejected         ReportStatus = "rejected"
+	ReportChangesRequested ReportStatus = "changes_requested"
+)
+
+// ExpenseReport groups expenses of one employee, e.g. of a trip, that are
+// approved as one unit. The report goes through approval as a single routing
+// expense and its decision applies to every member expense.
+type ExpenseReport struct {
+	ID               primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	CompanyID        primitive.ObjectID   `json:"company_id" bson:"company_id"`
+	UserID           primitive.ObjectID   `json:"user_id" bson:"user_id"`
+	Title            string               `json:"title" bson:"title"`
+	Purpose          string               `json:"purpose,omitempty" bson:"purpose,omitempty"`
+	PeriodStart      time.Time            `json:"period_start" bson:"period_start"`
+	PeriodEnd        time.Time            `json:"period_end" bson:"period_end"`
+	ExpenseIDs       []primitive.ObjectID `json:"expense_ids" bson:"expense_ids"`
+	BaseCurrency     string               `json:"base_currency" bson:"base_currency"`
+	TotalAmount      float64              `json:"total_amount" bson:"total_amount"`       // In the base currency
+	ApprovedAmount   float64              `json:"approved_amount" bson:"approved_amount"` // Total less the lines rejected after approval
+	Status           ReportStatus         `json:"status" bson:"status"`
+	RoutingExpenseID *primitive.ObjectID  `json:"routing_expense_id,omitempty" bson:"routing_expense_id,omitempty"` // Expense the approvals are attached to
+	RejectedLines    []ReportLineDecision `json:"rejected_lines,omitempty" bson:"rejected_lines,omitempty"`
+	Version          int                  `json:"version" bson:"version"` // Incremented on every write, guards concurrent updates
+	SubmittedAt      *time.Time           `json:"submitted_at,omitempty" bson:"submitted_at,omitempty"`
+	DecidedAt        *time.Time           `json:"decided_at,omitempty" bson:"decided_at,omitempty"`
+	CreatedAt        time.Time            `json:"created_at" bson:"created_at"`
+	UpdatedAt        time.Time            `json:"updated_at" bson:"updated_at"`
+}
+
+// ReportLineDecision records an expense rejected inside an approved report
+type ReportLineDecision struct {
+	ExpenseID primitive.ObjectID `json:"expense_id" bson:"expense_id"`
+	DecidedBy primitive.ObjectID `json:"decided_by" bson:"decided_by"`
+	Comments  string             `json:"comments" bson:"comments"`
+	DecidedAt time.Time          `json:"decided_at" bson:"decided_at"`
+}
+
+// AuditAction names a recorded change
+type AuditAction string
+
+const (
+	AuditExpenseCreated     AuditAction = "expense.created"
+	AuditExpenseSubmitted   AuditAction = "expense.submitted" // Draft was submitted for approval
+	AuditExpenseUpdated     AuditAction = "expense.updated"
+	AuditExpenseDeleted     AuditAction = "expense.deleted"
+	AuditExpenseWithdrawn   AuditAction = "expense.withdrawn"
+	AuditExpenseResubmitted AuditAction = "expense.resubmitted"
+	AuditExpenseDecided     AuditAction = "expense.decided"   // Expense reached approved or rejected
+	AuditExpenseScheduled   AuditAction = "expense.scheduled" // Expense was added to a reimbursement batch
+	AuditExpenseSplit       AuditAction = "expense.split"     // Expense was split into category allocations
+
+	AuditApprovalApproved         AuditAction = "approval.approved"
+	AuditApprovalRejected         AuditAction = "approval.rejected"
+	AuditApprovalChangesRequested AuditAction = "approval.changes_requested"
+	AuditApprovalEscalated        AuditAction = "approval.escalated"
+
+	AuditRuleCreated     AuditAction = "approval_rule.created"
+	AuditRuleUpdated     AuditAction = "approval_rule.updated"
+	AuditRuleActivated   AuditAction = "approval_rule.activated"
+	AuditRuleDeactivated AuditAction = "approval_rule.deactivated"
+	AuditRuleDeleted     AuditAction = "approval_rule.deleted"
+
+	AuditUserRoleChanged AuditAction = "user.role_changed"
+
+	AuditBatchCreated AuditAction = "reimbursement_batch.created"
+	AuditBatchPaid    AuditAction = "reimbursement_batch.paid"
+
+	AuditReportCreated      AuditAction = "expense_report.created"
+	AuditReportUpdated      AuditAction = "expense_report.updated"
+	AuditReportDeleted      AuditAction = "expense_report.deleted"
+	AuditReportSubmitted    AuditAction = "expense_report.submitted"
+	AuditReportDecided      AuditAction = "expense_report.decided"       // Report reached approved, rejected or changes requested
+	AuditReportLineRejected AuditAction = "expense_report.line_rejected" // Member expense rejected after the report was approved
+)
+
+// AuditEntry is an append-only record of a change. Entries of a company form a
+// chain: each hash covers the entry and the hash of the one before it, so any
+// edit or removal breaks every later link.
+type AuditEntry struct {
+	ID         primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	CompanyID  primitive.ObjectID  `json:"company_id" bson:"company_id"`
+	Sequence   int64               `json:"sequence" bson:"sequence"`                     // Position in the company's chain, from 1
+	ActorID    *primitive.ObjectID `json:"actor_id,omitempty" bson:"actor_id,omitempty"` // Nil for changes made by the system
+	IP         string              `json:"ip,omitempty" bson:"ip,omitempty"`
+	Action     AuditAction         `json:"action" bson:"action"`
+	EntityType string              `json:"entity_type" bson:"entity_type"`
+	EntityID   primitive.ObjectID  `json:"entity_id" bson:"entity_id"`
+	Before     json.RawMessage     `json:"before,omitempty" bson:"before,omitempty"` // JSON of the entity before the change
+	After      json.RawMessage     `json:"after,omitempty" bson:"after,omitempty"`   // JSON of the entity after the change
+	PrevHash   string              `json:"prev_hash" bson:"prev_hash"`
+	Hash       string              `json:"hash" bson:"hash"`
+	CreatedAt  time.Time           `json:"created_at" bson:"created_at"`
+}
+
 // OCRResult stores OCR extraction results
 type OCRResult struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
@@ -1,6 +1,20 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrConcurrentUpdate is returned when a conditional write lost against a
+// concurrent change of the same document
+var ErrConcurrentUpdate = errors.New("document was modified concurrently")
+
+// ErrNotFound is returned when a conditional write found no document at all,
+// as opposed to one that was modified concurrently
+var ErrNotFound = errors.New("document not found")
 
 // UserRepository defines methods for user data access
 type UserRepository interface {
@@ -12,6 +26,11 @@ type UserRepository interface {
 	Delete(ctx context.Context, id string) error
 	UpdateRole(ctx context.Context, id string, role UserRole) error
 	AssignManager(ctx context.Context, userID, managerID string) error
+	UpdateApprovalLimit(ctx context.Context, id string, limit *float64) error
+	UpdateDigestSettings(ctx context.Context, id string, settings *DigestSettings) error
+	FindDigestSubscribers(ctx context.Context) ([]*User, error)
+	ClaimDigest(ctx context.Context, id, localDate string) (bool, error)
+	ReleaseDigest(ctx context.Context, id, localDate, previous string) error
 }
 
 // CompanyRepository defines methods for company data access
@@ -23,16 +42,52 @@ type CompanyRepository interface {
 	Delete(ctx context.Context, id string) error
 }
 
+// ExpenseFilter narrows and orders an expense list. Zero fields do not filter.
+type ExpenseFilter struct {
+	Statuses    []ExpenseStatus
+	Categories  []ExpenseCategory
+	DateFrom    *time.Time // On ExpenseDate, inclusive
+	DateTo      *time.Time // On ExpenseDate, inclusive
+	MinAmount   *float64   // On ConvertedAmount, in company base currency
+	MaxAmount   *float64
+	Currency    string
+	Merchant    string // Case-insensitive exact match
+	SubmitterID string
+	Search      string // Words in description or merchant
+	SortBy      string // Field name, defaults to created_at
+	SortAsc     bool
+}
+
+// PageCursor is the position after the last item of a page in
+// (created_at, _id) order
+type PageCursor struct {
+	CreatedAt time.Time
+	ID        primitive.ObjectID
+}
+
 // ExpenseRepository defines methods for expense data access
 type ExpenseRepository interface {
 	Create(ctx context.Context, expense *Expense) error
 	FindByID(ctx context.Context, id string) (*Expense, error)
-	FindByUserID(ctx context.Context, userID string, page, limit int) ([]*Expense, int64, error)
-	FindByCompanyID(ctx context.Context, companyID string, page, limit int) ([]*Expense, int64, error)
+	FindByUserID(ctx context.Context, userID string, filter *ExpenseFilter, page, limit int) ([]*Expense, int64, error)
+	FindByCompanyID(ctx context.Context, companyID string, filter *ExpenseFilter, page, limit int) ([]*Expense, int64, error)
+	FindByUserIDAfter(ctx context.Context, userID string, filter *ExpenseFilter, after *PageCursor, limit int) ([]*Expense, error)
+	FindByCompanyIDAfter(ctx context.Context, companyID string, filter *ExpenseFilter, after *PageCursor, limit int) ([]*Expense, error)
 	Update(ctx context.Context, expense *Expense) error
 	Delete(ctx context.Context, id string) error
-	UpdateStatus(ctx context.Context, id string, status ExpenseStatus) error
+	TransitionStatus(ctx context.Context, expense *Expense, from, to ExpenseStatus) error
+	SubmitDraft(ctx context.Context, expense *Expense) error
 	FindPendingByCompanyID(ctx context.Context, companyID string) ([]*Expense, error)
+	FindPendingRoutingByCompanyID(ctx context.Context, companyID string) ([]*Expense, error)
+	StartApprovalRound(ctx context.Context, expense *Expense) error
+	FindApprovedUnbatchedByCompanyID(ctx context.Context, companyID string) ([]*Expense, error)
+	AssignToBatch(ctx context.Context, expense *Expense, batchID string) error
+	MarkBatchPaid(ctx context.Context, batchID string) (int64, error)
+	FindStaleDrafts(ctx context.Context, createdBefore time.Time) ([]*Expense, error)
+	FlagStaleDraft(ctx context.Context, expense *Expense) (bool, error)
+	FindByReportID(ctx context.Context, reportID string) ([]*Expense, error)
+	AssignToReport(ctx context.Context, expense *Expense, reportID string) error
+	RemoveFromReport(ctx context.Context, expense *Expense) error
 }
 
 // ApprovalRepository defines methods for approval data access
@@ -41,9 +96,15 @@ type ApprovalRepository interface {
 	FindByID(ctx context.Context, id string) (*Approval, error)
 	FindByExpenseID(ctx context.Context, expenseID string) ([]*Approval, error)
 	FindPendingByApproverID(ctx context.Context, approverID string) ([]*Approval, error)
+	FindOpenByApproverID(ctx context.Context, approverID string) ([]*Approval, error)
+	FindPendingAssignedBefore(ctx context.Context, before time.Time) ([]*Approval, error)
 	FindPendingByApproverIDWithDetails(ctx context.Context, approverID string) ([]*ApprovalWithDetails, error)
+	FindPendingByApproverIDAfter(ctx context.Context, approverID string, after *PageCursor, limit int) ([]*ApprovalWithDetails, error)
 	Update(ctx context.Context, approval *Approval) error
+	Transition(ctx context.Context, approval *Approval, from ApprovalStatus) error
 	UpdateStatus(ctx context.Context, id string, status ApprovalStatus) error
+	CancelOpenByExpenseID(ctx context.Context, expenseID string) error
+	DeleteByExpenseID(ctx context.Context, expenseID string) error
 	CountApprovedByExpenseID(ctx context.Context, expenseID string) (int64, error)
 	CountTotalByExpenseID(ctx context.Context, expenseID string) (int64, error)
 }
@@ -52,8 +113,39 @@ type ApprovalRepository interface {
 type ApprovalRuleRepository interface {
 	Create(ctx context.Context, rule *ApprovalRule) error
 	FindByID(ctx context.Context, id string) (*ApprovalRule, error)
-	FindByCompanyID(ctx context.Context, companyID string) (*ApprovalRule, error)
+	FindActiveByCompanyID(ctx context.Context, companyID string) ([]*ApprovalRule, error)
+	FindAllByCompanyID(ctx context.Context, companyID string) ([]*ApprovalRule, error)
 	Update(ctx context.Context, rule *ApprovalRule) error
+	SetActive(ctx context.Context, id string, isActive bool) error
+	ClearDefaultExcept(ctx context.Context, companyID, keepID string) error
+	Delete(ctx context.Context, id string) error
+}
+
+// DelegationRepository defines methods for delegation data access
+type DelegationRepository interface {
+	Create(ctx context.Context, delegation *Delegation) error
+	FindByID(ctx context.Context, id string) (*Delegation, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]*Delegation, error)
+	FindByUserID(ctx context.Context, userID string) ([]*Delegation, error)
+	FindActiveByDelegatorID(ctx context.Context, delegatorID string, at time.Time) ([]*Delegation, error)
+	Update(ctx context.Context, delegation *Delegation) error
+}
+
+// ReimbursementBatchRepository defines methods for reimbursement batch data access
+type ReimbursementBatchRepository interface {
+	Create(ctx context.Context, batch *ReimbursementBatch) error
+	FindByID(ctx context.Context, id string) (*ReimbursementBatch, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]*ReimbursementBatch, error)
+	MarkPaid(ctx context.Context, batch *ReimbursementBatch) error
+}
+
+// ExpenseReportRepository defines methods for expense report data access
+type ExpenseReportRepository interface {
+	Create(ctx context.Context, report *ExpenseReport) error
+	FindByID(ctx context.Context, id string) (*ExpenseReport, error)
+	FindByUserID(ctx context.Context, userID string) ([]*ExpenseReport, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]*ExpenseReport, error)
+	Update(ctx context.Context, report *ExpenseReport) error
 	Delete(ctx context.Context, id string) error
 }
 
@@ -64,3 +156,12 @@ type OCRResultRepository interface {
 	FindByReceiptURL(ctx context.Context, receiptURL string) (*OCRResult, error)
 	FindByUserID(ctx context.Context, userID string) ([]*OCRResult, error)
 }
+
+// AuditRepository defines methods for audit trail data access. Entries are
+// never updated or deleted.
+type AuditRepository interface {
+	Append(ctx context.Context, entry *AuditEntry) error
+	FindLastByCompanyID(ctx context.Context, companyID string) (*AuditEntry, error)
+	FindByCompanyID(ctx context.Context, companyID string) ([]*AuditEntry, error)
+	FindByEntityID(ctx context.Context, companyID, entityID string) ([]*AuditEntry, error)
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"strconv"
+
 	"expensio-backend/internal/config"
 	"expensio-backend/internal/service"
 	"expensio-backend/pkg/response"
@@ -27,6 +29,27 @@ func NewApprovalHandler(approvalService *service.ApprovalService, cfg *config.Co
 func (h *ApprovalHandler) GetPendingApprovals(c *fiber.Ctx) error {
 	approverID := c.Locals("userID").(string)
 
+	// Paged by cursor when asked to, the whole list otherwise
+	if c.Context().QueryArgs().Has("cursor") || c.Query("limit") != "" {
+		limit, _ := strconv.Atoi(c.Query("limit", "10"))
+		if err := validator.ValidatePagination(1, limit); err != nil {
+			return response.ValidationError(c, err.Error())
+		}
+
+		approvals, next, err := h.approvalService.GetPendingApprovalsAfter(c.Context(), approverID, c.Query("cursor"), limit)
+		if err != nil {
+			return response.ValidationError(c, err.Error())
+		}
+
+		meta := fiber.Map{
+			"limit":       limit,
+			"next_cursor": next,
+			"has_more":    next != "",
+		}
+
+		return response.SuccessWithMeta(c, fiber.StatusOK, "Pending approvals retrieved successfully", approvals, meta)
+	}
+
 	approvals, err := h.approvalService.GetPendingApprovalsWithDetails(c.Context(), approverID)
 	if err != nil {
 		return response.InternalServerError(c, "Failed to fetch pending approvals")
@@ -81,6 +104,95 @@ func (h *ApprovalHandler) RejectExpense(c *fiber.Ctx) error {
 	return response.OK(c, "Expense rejected successfully", nil)
 }
 
+// RequestChanges sends an expense back to its submitter via approval ID
+// @route POST /api/v1/approvals/:id/request-changes
+func (h *ApprovalHandler) RequestChanges(c *fiber.Ctx) error {
+	approvalID := c.Params("id")
+	approverID := c.Locals("userID").(string)
+
+	if err := validator.ValidateObjectID(approvalID); err != nil {
+		return response.BadRequest(c, "Invalid approval ID")
+	}
+
+	var req service.ApprovalActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	if err := h.approvalService.RequestChangesByApprovalID(c.Context(), approvalID, approverID, &req); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Changes requested successfully", nil)
+}
+
+// CreateActionLinks mints signed one-time approve and reject links for an approval
+// @route POST /api/v1/approvals/:id/action-links
+func (h *ApprovalHandler) CreateActionLinks(c *fiber.Ctx) error {
+	approvalID := c.Params("id")
+	approverID := c.Locals("userID").(string)
+
+	if err := validator.ValidateObjectID(approvalID); err != nil {
+		return response.BadRequest(c, "Invalid approval ID")
+	}
+
+	links, err := h.approvalService.CreateActionLinks(c.Context(), approvalID, approverID)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.Created(c, "Action links created successfully", links)
+}
+
+// PreviewActionLink shows what a signed action link will do, for confirmation (public)
+// @route GET /api/v1/action-links/:token
+func (h *ApprovalHandler) PreviewActionLink(c *fiber.Ctx) error {
+	preview, err := h.approvalService.PreviewActionLink(c.Context(), c.Params("token"))
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Confirm to "+string(preview.Action)+" this expense", preview)
+}
+
+// ConfirmActionLink performs the decision of a signed action link (public)
+// @route POST /api/v1/action-links/:token
+func (h *ApprovalHandler) ConfirmActionLink(c *fiber.Ctx) error {
+	var req service.ApprovalActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		// Comments are optional, so we can ignore parse errors
+		req.Comments = ""
+	}
+
+	action, err := h.approvalService.ConfirmActionLink(c.Context(), c.Params("token"), &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	if action == service.BulkApprove {
+		return response.OK(c, "Expense approved successfully", nil)
+	}
+	return response.OK(c, "Expense rejected successfully", nil)
+}
+
+// BulkProcessApprovals approves or rejects several approvals at once
+// @route POST /api/v1/approvals/bulk
+func (h *ApprovalHandler) BulkProcessApprovals(c *fiber.Ctx) error {
+	approverID := c.Locals("userID").(string)
+
+	var req service.BulkApprovalRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	result, err := h.approvalService.BulkProcessApprovals(c.Context(), approverID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Approvals processed", result)
+}
+
 // GetApprovalHistory retrieves approval history for an expense
 // @route GET /api/v1/approvals/history/:expenseId
 func (h *ApprovalHandler) GetApprovalHistory(c *fiber.Ctx) error {
@@ -97,3 +209,47 @@ func (h *ApprovalHandler) GetApprovalHistory(c *fiber.Ctx) error {
 
 	return response.OK(c, "Approval history retrieved successfully", approvals)
 }
+
+// RerouteExpenses re-routes pending expenses under the current approval rule (Admin only)
+// @route POST /api/v1/approvals/reroute
+func (h *ApprovalHandler) RerouteExpenses(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+
+	var req service.RerouteRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return response.BadRequest(c, "Invalid request body")
+		}
+	}
+
+	for _, expenseID := range req.ExpenseIDs {
+		if err := validator.ValidateObjectID(expenseID); err != nil {
+			return response.BadRequest(c, "Invalid expense ID: "+expenseID)
+		}
+	}
+
+	result, err := h.approvalService.RerouteExpenses(c.Context(), companyID, &req)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to re-route expenses")
+	}
+
+	return response.OK(c, "Expenses re-routed successfully", result)
+}
+
+// SimulateRoute previews how a hypothetical expense would be routed (Admin only)
+// @route POST /api/v1/approvals/simulate
+func (h *ApprovalHandler) SimulateRoute(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+
+	var req service.SimulationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	plan, err := h.approvalService.SimulateRoute(c.Context(), companyID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Approval route simulated successfully", plan)
+}
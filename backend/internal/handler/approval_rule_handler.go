@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/service"
+	"expensio-backend/pkg/response"
+	"expensio-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ApprovalRuleHandler struct {
+	approvalRuleService *service.ApprovalRuleService
+	cfg                 *config.Config
+}
+
+// NewApprovalRuleHandler creates a new approval rule handler
+func NewApprovalRuleHandler(approvalRuleService *service.ApprovalRuleService, cfg *config.Config) *ApprovalRuleHandler {
+	return &ApprovalRuleHandler{
+		approvalRuleService: approvalRuleService,
+		cfg:                 cfg,
+	}
+}
+
+// CreateRule creates a new approval rule (Admin only)
+// @route POST /api/v1/approval-rules
+func (h *ApprovalRuleHandler) CreateRule(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+
+	var req service.ApprovalRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	rule, err := h.approvalRuleService.CreateRule(c.Context(), companyID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.Created(c, "Approval rule created successfully", rule)
+}
+
+// GetRules retrieves all approval rules of the company
+// @route GET /api/v1/approval-rules
+func (h *ApprovalRuleHandler) GetRules(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+
+	rules, err := h.approvalRuleService.GetRules(c.Context(), companyID)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to fetch approval rules")
+	}
+
+	return response.OK(c, "Approval rules retrieved successfully", rules)
+}
+
+// GetRule retrieves a single approval rule by ID
+// @route GET /api/v1/approval-rules/:id
+func (h *ApprovalRuleHandler) GetRule(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	ruleID := c.Params("id")
+
+	if err := validator.ValidateObjectID(ruleID); err != nil {
+		return response.BadRequest(c, "Invalid approval rule ID")
+	}
+
+	rule, err := h.approvalRuleService.GetRule(c.Context(), companyID, ruleID)
+	if err != nil {
+		return response.NotFound(c, "Approval rule not found")
+	}
+
+	return response.OK(c, "Approval rule retrieved successfully", rule)
+}
+
+// UpdateRule updates an approval rule (Admin only)
+// @route PUT /api/v1/approval-rules/:id
+func (h *ApprovalRuleHandler) UpdateRule(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	ruleID := c.Params("id")
+
+	if err := validator.ValidateObjectID(ruleID); err != nil {
+		return response.BadRequest(c, "Invalid approval rule ID")
+	}
+
+	var req service.ApprovalRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	rule, err := h.approvalRuleService.UpdateRule(c.Context(), companyID, ruleID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Approval rule updated successfully", rule)
+}
+
+// ActivateRule makes a rule the company's active approval rule (Admin only)
+// @route PUT /api/v1/approval-rules/:id/activate
+func (h *ApprovalRuleHandler) ActivateRule(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	ruleID := c.Params("id")
+
+	if err := validator.ValidateObjectID(ruleID); err != nil {
+		return response.BadRequest(c, "Invalid approval rule ID")
+	}
+
+	if err := h.approvalRuleService.ActivateRule(c.Context(), companyID, ruleID); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Approval rule activated successfully", nil)
+}
+
+// DeactivateRule deactivates an approval rule (Admin only)
+// @route PUT /api/v1/approval-rules/:id/deactivate
+func (h *ApprovalRuleHandler) DeactivateRule(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	ruleID := c.Params("id")
+
+	if err := validator.ValidateObjectID(ruleID); err != nil {
+		return response.BadRequest(c, "Invalid approval rule ID")
+	}
+
+	if err := h.approvalRuleService.DeactivateRule(c.Context(), companyID, ruleID); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Approval rule deactivated successfully", nil)
+}
+
+// DeleteRule deletes an approval rule (Admin only)
+// @route DELETE /api/v1/approval-rules/:id
+func (h *ApprovalRuleHandler) DeleteRule(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	ruleID := c.Params("id")
+
+	if err := validator.ValidateObjectID(ruleID); err != nil {
+		return response.BadRequest(c, "Invalid approval rule ID")
+	}
+
+	if err := h.approvalRuleService.DeleteRule(c.Context(), companyID, ruleID); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Approval rule deleted successfully", nil)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/service"
+	"expensio-backend/pkg/response"
+	"expensio-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+	cfg          *config.Config
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *service.AuditService, cfg *config.Config) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+		cfg:          cfg,
+	}
+}
+
+// VerifyChain verifies the company's audit chain (Admin only)
+// @route GET /api/v1/audit/verify
+func (h *AuditHandler) VerifyChain(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+
+	result, err := h.auditService.VerifyChain(c.Context(), companyID)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to verify audit trail")
+	}
+
+	return response.OK(c, "Audit trail verified", result)
+}
+
+// GetEntityTrail retrieves the audit entries of an expense, approval, rule, user or reimbursement batch (Admin only)
+// @route GET /api/v1/audit/entities/:id
+func (h *AuditHandler) GetEntityTrail(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	entityID := c.Params("id")
+
+	if err := validator.ValidateObjectID(entityID); err != nil {
+		return response.BadRequest(c, "Invalid entity ID")
+	}
+
+	entries, err := h.auditService.GetEntityTrail(c.Context(), companyID, entityID)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to fetch audit trail")
+	}
+
+	return response.OK(c, "Audit trail retrieved successfully", entries)
+}
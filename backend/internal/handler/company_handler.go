@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/service"
+	"expensio-backend/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CompanyHandler struct {
+	companyService *service.CompanyService
+	cfg            *config.Config
+}
+
+// NewCompanyHandler creates a new company handler
+func NewCompanyHandler(companyService *service.CompanyService, cfg *config.Config) *CompanyHandler {
+	return &CompanyHandler{
+		companyService: companyService,
+		cfg:            cfg,
+	}
+}
+
+// GetSettings retrieves the company's workflow settings
+// @route GET /api/v1/company/settings
+func (h *CompanyHandler) GetSettings(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+
+	settings, err := h.companyService.GetSettings(c.Context(), companyID)
+	if err != nil {
+		return response.NotFound(c, "Company not found")
+	}
+
+	return response.OK(c, "Company settings retrieved successfully", settings)
+}
+
+// UpdateSettings updates the company's workflow settings (Admin only)
+// @route PUT /api/v1/company/settings
+func (h *CompanyHandler) UpdateSettings(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+
+	var req service.CompanySettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	settings, err := h.companyService.UpdateSettings(c.Context(), companyID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Company settings updated successfully", settings)
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/service"
+	"expensio-backend/pkg/response"
+	"expensio-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DelegationHandler struct {
+	delegationService *service.DelegationService
+	cfg               *config.Config
+}
+
+// NewDelegationHandler creates a new delegation handler
+func NewDelegationHandler(delegationService *service.DelegationService, cfg *config.Config) *DelegationHandler {
+	return &DelegationHandler{
+		delegationService: delegationService,
+		cfg:               cfg,
+	}
+}
+
+// CreateDelegation creates an out-of-office delegation
+// @route POST /api/v1/delegations
+func (h *DelegationHandler) CreateDelegation(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	userID := c.Locals("userID").(string)
+	role := c.Locals("role").(string)
+
+	var req service.DelegationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	result, err := h.delegationService.CreateDelegation(c.Context(), companyID, userID, role, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.Created(c, "Delegation created successfully", result)
+}
+
+// GetDelegations retrieves delegations visible to the user
+// @route GET /api/v1/delegations
+func (h *DelegationHandler) GetDelegations(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	userID := c.Locals("userID").(string)
+	role := c.Locals("role").(string)
+
+	delegations, err := h.delegationService.GetDelegations(c.Context(), companyID, userID, role)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to fetch delegations")
+	}
+
+	return response.OK(c, "Delegations retrieved successfully", delegations)
+}
+
+// GetDelegation retrieves a single delegation by ID
+// @route GET /api/v1/delegations/:id
+func (h *DelegationHandler) GetDelegation(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	userID := c.Locals("userID").(string)
+	role := c.Locals("role").(string)
+	delegationID := c.Params("id")
+
+	if err := validator.ValidateObjectID(delegationID); err != nil {
+		return response.BadRequest(c, "Invalid delegation ID")
+	}
+
+	delegation, err := h.delegationService.GetDelegation(c.Context(), companyID, userID, role, delegationID)
+	if err != nil {
+		return response.NotFound(c, "Delegation not found")
+	}
+
+	return response.OK(c, "Delegation retrieved successfully", delegation)
+}
+
+// UpdateDelegation updates a delegation
+// @route PUT /api/v1/delegations/:id
+func (h *DelegationHandler) UpdateDelegation(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	userID := c.Locals("userID").(string)
+	role := c.Locals("role").(string)
+	delegationID := c.Params("id")
+
+	if err := validator.ValidateObjectID(delegationID); err != nil {
+		return response.BadRequest(c, "Invalid delegation ID")
+	}
+
+	var req service.DelegationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	result, err := h.delegationService.UpdateDelegation(c.Context(), companyID, userID, role, delegationID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Delegation updated successfully", result)
+}
+
+// RevokeDelegation revokes a delegation
+// @route DELETE /api/v1/delegations/:id
+func (h *DelegationHandler) RevokeDelegation(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	userID := c.Locals("userID").(string)
+	role := c.Locals("role").(string)
+	delegationID := c.Params("id")
+
+	if err := validator.ValidateObjectID(delegationID); err != nil {
+		return response.BadRequest(c, "Invalid delegation ID")
+	}
+
+	if err := h.delegationService.RevokeDelegation(c.Context(), companyID, userID, role, delegationID); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Delegation revoked successfully", nil)
+}
+
+// ReassignPending moves the delegator's pending approvals to the delegate
+// @route POST /api/v1/delegations/:id/reassign
+func (h *DelegationHandler) ReassignPending(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	userID := c.Locals("userID").(string)
+	role := c.Locals("role").(string)
+	delegationID := c.Params("id")
+
+	if err := validator.ValidateObjectID(delegationID); err != nil {
+		return response.BadRequest(c, "Invalid delegation ID")
+	}
+
+	result, err := h.delegationService.ReassignPending(c.Context(), companyID, userID, role, delegationID)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Pending approvals reassigned successfully", result)
+}
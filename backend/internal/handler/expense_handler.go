@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"expensio-backend/internal/config"
+	"expensio-backend/internal/domain"
 	"expensio-backend/internal/service"
 	"expensio-backend/pkg/response"
 	"expensio-backend/pkg/validator"
@@ -34,17 +38,8 @@ func (h *ExpenseHandler) CreateExpense(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Invalid request body")
 	}
 
-	// Validate request
-	if err := validator.ValidateAmount(req.Amount); err != nil {
-		return response.ValidationError(c, err.Error())
-	}
-	if err := validator.ValidateCurrency(req.Currency); err != nil {
-		return response.ValidationError(c, err.Error())
-	}
-	if err := validator.ValidateCategory(string(req.Category)); err != nil {
-		return response.ValidationError(c, err.Error())
-	}
-	if err := validator.ValidateDescription(req.Description); err != nil {
+	// Validate request, drafts may leave fields empty
+	if err := validateExpenseRequest(&req, req.Draft); err != nil {
 		return response.ValidationError(c, err.Error())
 	}
 
@@ -71,10 +66,15 @@ func (h *ExpenseHandler) GetExpense(c *fiber.Ctx) error {
 		return response.NotFound(c, "Expense not found")
 	}
 
+	// Drafts stay private to their owner
+	if expense.Status == domain.StatusDraft && expense.UserID.Hex() != c.Locals("userID").(string) {
+		return response.NotFound(c, "Expense not found")
+	}
+
 	return response.OK(c, "Expense retrieved successfully", expense)
 }
 
-// GetExpenses retrieves expenses with pagination
+// GetExpenses retrieves expenses with pagination, filters and sorting
 // @route GET /api/v1/expenses
 func (h *ExpenseHandler) GetExpenses(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
@@ -89,13 +89,31 @@ func (h *ExpenseHandler) GetExpenses(c *fiber.Ctx) error {
 		return response.ValidationError(c, err.Error())
 	}
 
+	filter, err := parseExpenseFilter(c)
+	if err != nil {
+		return response.ValidationError(c, err.Error())
+	}
+
+	// Employees only ever see their own expenses
+	if role != "admin" && role != "manager" {
+		filter.SubmitterID = ""
+	}
+
+	// A cursor, even an empty one to start, switches from pages to cursors
+	if c.Context().QueryArgs().Has("cursor") {
+		return h.getExpensesByCursor(c, userID, companyID, role, filter, limit)
+	}
+
 	var expenses []*interface{}
 	var total int64
-	var err error
+	var last *domain.Expense
 
 	// Admins and Managers can see all company expenses
 	if role == "admin" || role == "manager" {
-		result, t, e := h.expenseService.GetCompanyExpenses(c.Context(), companyID, page, limit)
+		result, t, e := h.expenseService.GetCompanyExpenses(c.Context(), companyID, filter, page, limit)
+		if len(result) > 0 {
+			last = result[len(result)-1]
+		}
 		expenses = make([]*interface{}, len(result))
 		for i, v := range result {
 			var temp interface{} = v
@@ -105,7 +123,10 @@ func (h *ExpenseHandler) GetExpenses(c *fiber.Ctx) error {
 		err = e
 	} else {
 		// Employees see only their expenses
-		result, t, e := h.expenseService.GetUserExpenses(c.Context(), userID, page, limit)
+		result, t, e := h.expenseService.GetUserExpenses(c.Context(), userID, filter, page, limit)
+		if len(result) > 0 {
+			last = result[len(result)-1]
+		}
 		expenses = make([]*interface{}, len(result))
 		for i, v := range result {
 			var temp interface{} = v
@@ -126,6 +147,13 @@ func (h *ExpenseHandler) GetExpenses(c *fiber.Ctx) error {
 		"totalPages": (total + int64(limit) - 1) / int64(limit),
 	}
 
+	// Lets clients continue from here with cursors, which inserts do not shift
+	if last != nil && int64(page*limit) < total {
+		if next := h.expenseService.ExpenseCursor(filter, last); next != "" {
+			meta["next_cursor"] = next
+		}
+	}
+
 	return response.SuccessWithMeta(c, fiber.StatusOK, "Expenses retrieved successfully", expenses, meta)
 }
 
@@ -133,6 +161,7 @@ func (h *ExpenseHandler) GetExpenses(c *fiber.Ctx) error {
 // @route PUT /api/v1/expenses/:id
 func (h *ExpenseHandler) UpdateExpense(c *fiber.Ctx) error {
 	expenseID := c.Params("id")
+	userID := c.Locals("userID").(string)
 
 	if err := validator.ValidateObjectID(expenseID); err != nil {
 		return response.BadRequest(c, "Invalid expense ID")
@@ -143,37 +172,106 @@ func (h *ExpenseHandler) UpdateExpense(c *fiber.Ctx) error {
 		return response.BadRequest(c, "Invalid request body")
 	}
 
-	// Validate request
-	if err := validator.ValidateAmount(req.Amount); err != nil {
+	// Validate the given fields, only a draft may stay incomplete, which the service checks
+	if err := validateExpenseRequest(&req, true); err != nil {
 		return response.ValidationError(c, err.Error())
 	}
-	if err := validator.ValidateCurrency(req.Currency); err != nil {
-		return response.ValidationError(c, err.Error())
+
+	if err := h.expenseService.UpdateExpense(c.Context(), expenseID, userID, &req); err != nil {
+		return response.BadRequest(c, err.Error())
 	}
-	if err := validator.ValidateCategory(string(req.Category)); err != nil {
-		return response.ValidationError(c, err.Error())
+
+	return response.OK(c, "Expense updated successfully", nil)
+}
+
+// ResubmitExpense sends an expense with requested changes back into approval
+// @route POST /api/v1/expenses/:id/resubmit
+func (h *ExpenseHandler) ResubmitExpense(c *fiber.Ctx) error {
+	expenseID := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	if err := validator.ValidateObjectID(expenseID); err != nil {
+		return response.BadRequest(c, "Invalid expense ID")
 	}
-	if err := validator.ValidateDescription(req.Description); err != nil {
-		return response.ValidationError(c, err.Error())
+
+	expense, err := h.expenseService.ResubmitExpense(c.Context(), expenseID, userID)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Expense resubmitted successfully", expense)
+}
+
+// WithdrawExpense takes a pending expense out of approval (submitter only)
+// @route POST /api/v1/expenses/:id/withdraw
+func (h *ExpenseHandler) WithdrawExpense(c *fiber.Ctx) error {
+	expenseID := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	if err := validator.ValidateObjectID(expenseID); err != nil {
+		return response.BadRequest(c, "Invalid expense ID")
 	}
 
-	if err := h.expenseService.UpdateExpense(c.Context(), expenseID, &req); err != nil {
+	expense, err := h.expenseService.WithdrawExpense(c.Context(), expenseID, userID)
+	if err != nil {
 		return response.BadRequest(c, err.Error())
 	}
 
-	return response.OK(c, "Expense updated successfully", nil)
+	return response.OK(c, "Expense withdrawn successfully", expense)
+}
+
+// SplitExpense allocates an expense to several categories (owner)
+// @route POST /api/v1/expenses/:id/split
+func (h *ExpenseHandler) SplitExpense(c *fiber.Ctx) error {
+	expenseID := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	if err := validator.ValidateObjectID(expenseID); err != nil {
+		return response.BadRequest(c, "Invalid expense ID")
+	}
+
+	var req service.SplitExpenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	expense, err := h.expenseService.SplitExpense(c.Context(), expenseID, userID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Expense split successfully", expense)
+}
+
+// SubmitExpense submits a draft for approval (owner)
+// @route POST /api/v1/expenses/:id/submit
+func (h *ExpenseHandler) SubmitExpense(c *fiber.Ctx) error {
+	expenseID := c.Params("id")
+	userID := c.Locals("userID").(string)
+
+	if err := validator.ValidateObjectID(expenseID); err != nil {
+		return response.BadRequest(c, "Invalid expense ID")
+	}
+
+	expense, err := h.expenseService.SubmitExpense(c.Context(), expenseID, userID)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Expense submitted successfully", expense)
 }
 
 // DeleteExpense deletes an expense
 // @route DELETE /api/v1/expenses/:id
 func (h *ExpenseHandler) DeleteExpense(c *fiber.Ctx) error {
 	expenseID := c.Params("id")
+	userID := c.Locals("userID").(string)
 
 	if err := validator.ValidateObjectID(expenseID); err != nil {
 		return response.BadRequest(c, "Invalid expense ID")
 	}
 
-	if err := h.expenseService.DeleteExpense(c.Context(), expenseID); err != nil {
+	if err := h.expenseService.DeleteExpense(c.Context(), expenseID, userID); err != nil {
 		return response.BadRequest(c, err.Error())
 	}
 
@@ -192,3 +290,180 @@ func (h *ExpenseHandler) GetPendingExpenses(c *fiber.Ctx) error {
 
 	return response.OK(c, "Pending expenses retrieved successfully", expenses)
 }
+
+// getExpensesByCursor retrieves the page of expenses after the cursor
+func (h *ExpenseHandler) getExpensesByCursor(c *fiber.Ctx, userID, companyID, role string, filter *domain.ExpenseFilter, limit int) error {
+	cursor := c.Query("cursor")
+
+	var result []*domain.Expense
+	var next string
+	var err error
+	if role == "admin" || role == "manager" {
+		result, next, err = h.expenseService.GetCompanyExpensesAfter(c.Context(), companyID, filter, cursor, limit)
+	} else {
+		result, next, err = h.expenseService.GetUserExpensesAfter(c.Context(), userID, filter, cursor, limit)
+	}
+	if err != nil {
+		return response.ValidationError(c, err.Error())
+	}
+
+	meta := fiber.Map{
+		"limit":       limit,
+		"next_cursor": next,
+		"has_more":    next != "",
+	}
+
+	return response.SuccessWithMeta(c, fiber.StatusOK, "Expenses retrieved successfully", result, meta)
+}
+
+// validateExpenseRequest validates the expense fields. Partial requests, for
+// drafts, may leave fields empty but not fill them with invalid values. An
+// itemized expense defaults to the category of its largest line.
+func validateExpenseRequest(req *service.CreateExpenseRequest, partial bool) error {
+	if !partial || req.Amount != 0 {
+		if err := validator.ValidateAmount(req.Amount); err != nil {
+			return err
+		}
+	}
+	if !partial || req.Currency != "" {
+		if err := validator.ValidateCurrency(req.Currency); err != nil {
+			return err
+		}
+	}
+	if (!partial && len(req.LineItems) == 0) || req.Category != "" {
+		if err := validator.ValidateCategory(string(req.Category)); err != nil {
+			return err
+		}
+	}
+	if !partial || req.Description != "" {
+		if err := validator.ValidateDescription(req.Description); err != nil {
+			return err
+		}
+	}
+	if !partial || !req.ExpenseDate.IsZero() {
+		if err := validator.ValidateExpenseDate(req.ExpenseDate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseExpenseFilter reads the list filters from the query string. Statuses
+// and categories take comma-separated lists, dates YYYY-MM-DD or RFC 3339.
+func parseExpenseFilter(c *fiber.Ctx) (*domain.ExpenseFilter, error) {
+	filter := &domain.ExpenseFilter{
+		Currency: strings.ToUpper(strings.TrimSpace(c.Query("currency"))),
+		Merchant: strings.TrimSpace(c.Query("merchant")),
+		Search:   strings.TrimSpace(c.Query("q")),
+	}
+
+	for _, status := range splitQueryList(c.Query("status")) {
+		if err := validator.ValidateExpenseStatus(status); err != nil {
+			return nil, err
+		}
+		filter.Statuses = append(filter.Statuses, domain.ExpenseStatus(status))
+	}
+	for _, category := range splitQueryList(c.Query("category")) {
+		if err := validator.ValidateCategory(category); err != nil {
+			return nil, err
+		}
+		filter.Categories = append(filter.Categories, domain.ExpenseCategory(strings.ToLower(category)))
+	}
+
+	if filter.Currency != "" {
+		if err := validator.ValidateCurrency(filter.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	if submitterID := c.Query("submitter_id"); submitterID != "" {
+		if err := validator.ValidateObjectID(submitterID); err != nil {
+			return nil, fmt.Errorf("invalid submitter_id")
+		}
+		filter.SubmitterID = submitterID
+	}
+
+	var err error
+	if filter.DateFrom, err = parseQueryDate(c.Query("date_from"), false); err != nil {
+		return nil, fmt.Errorf("invalid date_from: %w", err)
+	}
+	if filter.DateTo, err = parseQueryDate(c.Query("date_to"), true); err != nil {
+		return nil, fmt.Errorf("invalid date_to: %w", err)
+	}
+	if filter.DateFrom != nil && filter.DateTo != nil && filter.DateTo.Before(*filter.DateFrom) {
+		return nil, fmt.Errorf("date_to must not be before date_from")
+	}
+
+	if filter.MinAmount, err = parseQueryAmount(c.Query("min_amount")); err != nil {
+		return nil, fmt.Errorf("invalid min_amount")
+	}
+	if filter.MaxAmount, err = parseQueryAmount(c.Query("max_amount")); err != nil {
+		return nil, fmt.Errorf("invalid max_amount")
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MaxAmount < *filter.MinAmount {
+		return nil, fmt.Errorf("max_amount must not be below min_amount")
+	}
+
+	switch sort := c.Query("sort"); sort {
+	case "", "created_at":
+	case "expense_date", "amount", "merchant", "category", "status":
+		filter.SortBy = sort
+	default:
+		return nil, fmt.Errorf("invalid sort: must be one of created_at, expense_date, amount, merchant, category, status")
+	}
+
+	switch order := strings.ToLower(c.Query("order")); order {
+	case "", "desc":
+	case "asc":
+		filter.SortAsc = true
+	default:
+		return nil, fmt.Errorf("invalid order: must be asc or desc")
+	}
+
+	return filter, nil
+}
+
+// splitQueryList splits a comma-separated query value, skipping empty items
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseQueryDate parses a YYYY-MM-DD or RFC 3339 date. A bare date ending a
+// range covers that whole day.
+func parseQueryDate(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		if endOfDay {
+			date = date.Add(24*time.Hour - time.Nanosecond)
+		}
+		return &date, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("use YYYY-MM-DD or RFC 3339")
+	}
+	return &date, nil
+}
+
+// parseQueryAmount parses an optional non-negative amount
+func parseQueryAmount(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return nil, fmt.Errorf("invalid amount")
+	}
+	return &amount, nil
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/service"
+	"expensio-backend/pkg/response"
+	"expensio-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExpenseReportHandler struct {
+	reportService *service.ExpenseReportService
+	cfg           *config.Config
+}
+
+// NewExpenseReportHandler creates a new expense report handler
+func NewExpenseReportHandler(reportService *service.ExpenseReportService, cfg *config.Config) *ExpenseReportHandler {
+	return &ExpenseReportHandler{
+		reportService: reportService,
+		cfg:           cfg,
+	}
+}
+
+// CreateReport creates an empty draft expense report
+// @route POST /api/v1/reports
+func (h *ExpenseReportHandler) CreateReport(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req service.ExpenseReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	report, err := h.reportService.CreateReport(c.Context(), userID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.Created(c, "Expense report created successfully", report)
+}
+
+// GetReports retrieves the user's reports, or the company's submitted reports
+// for admins and managers
+// @route GET /api/v1/reports
+func (h *ExpenseReportHandler) GetReports(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	companyID := c.Locals("companyID").(string)
+	role := c.Locals("role").(string)
+
+	company := (role == "admin" || role == "manager") && c.Query("scope") != "mine"
+
+	reports, err := h.reportService.GetReports(c.Context(), companyID, userID, company)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to fetch expense reports")
+	}
+
+	return response.OK(c, "Expense reports retrieved successfully", reports)
+}
+
+// GetReport retrieves an expense report with its expenses
+// @route GET /api/v1/reports/:id
+func (h *ExpenseReportHandler) GetReport(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	companyID := c.Locals("companyID").(string)
+	role := c.Locals("role").(string)
+	reportID := c.Params("id")
+
+	if err := validator.ValidateObjectID(reportID); err != nil {
+		return response.BadRequest(c, "Invalid report ID")
+	}
+
+	report, err := h.reportService.GetReport(c.Context(), companyID, userID, role, reportID)
+	if err != nil {
+		return response.NotFound(c, "Expense report not found")
+	}
+
+	return response.OK(c, "Expense report retrieved successfully", report)
+}
+
+// UpdateReport changes the title, purpose and period of the owner's report
+// @route PUT /api/v1/reports/:id
+func (h *ExpenseReportHandler) UpdateReport(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	reportID := c.Params("id")
+
+	if err := validator.ValidateObjectID(reportID); err != nil {
+		return response.BadRequest(c, "Invalid report ID")
+	}
+
+	var req service.ExpenseReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	report, err := h.reportService.UpdateReport(c.Context(), userID, reportID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Expense report updated successfully", report)
+}
+
+// DeleteReport deletes the owner's report that was never submitted
+// @route DELETE /api/v1/reports/:id
+func (h *ExpenseReportHandler) DeleteReport(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	reportID := c.Params("id")
+
+	if err := validator.ValidateObjectID(reportID); err != nil {
+		return response.BadRequest(c, "Invalid report ID")
+	}
+
+	if err := h.reportService.DeleteReport(c.Context(), userID, reportID); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Expense report deleted successfully", nil)
+}
+
+// AddExpenses adds the owner's draft expenses to the report
+// @route POST /api/v1/reports/:id/expenses
+func (h *ExpenseReportHandler) AddExpenses(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	reportID := c.Params("id")
+
+	if err := validator.ValidateObjectID(reportID); err != nil {
+		return response.BadRequest(c, "Invalid report ID")
+	}
+
+	var req service.AddReportExpensesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+	for _, expenseID := range req.ExpenseIDs {
+		if err := validator.ValidateObjectID(expenseID); err != nil {
+			return response.ValidationError(c, "Invalid expense ID: "+expenseID)
+		}
+	}
+
+	report, err := h.reportService.AddExpenses(c.Context(), userID, reportID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Expenses added to report successfully", report)
+}
+
+// RemoveExpense takes an expense out of the owner's report
+// @route DELETE /api/v1/reports/:id/expenses/:expenseId
+func (h *ExpenseReportHandler) RemoveExpense(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	reportID := c.Params("id")
+	expenseID := c.Params("expenseId")
+
+	if err := validator.ValidateObjectID(reportID); err != nil {
+		return response.BadRequest(c, "Invalid report ID")
+	}
+	if err := validator.ValidateObjectID(expenseID); err != nil {
+		return response.BadRequest(c, "Invalid expense ID")
+	}
+
+	report, err := h.reportService.RemoveExpense(c.Context(), userID, reportID, expenseID)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Expense removed from report successfully", report)
+}
+
+// SubmitReport sends the owner's report into approval as one unit
+// @route POST /api/v1/reports/:id/submit
+func (h *ExpenseReportHandler) SubmitReport(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	reportID := c.Params("id")
+
+	if err := validator.ValidateObjectID(reportID); err != nil {
+		return response.BadRequest(c, "Invalid report ID")
+	}
+
+	report, err := h.reportService.SubmitReport(c.Context(), userID, reportID)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Expense report submitted successfully", report)
+}
+
+// RejectLine rejects one expense of an approved report (Admin and the
+// report's approvers)
+// @route POST /api/v1/reports/:id/expenses/:expenseId/reject
+func (h *ExpenseReportHandler) RejectLine(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	companyID := c.Locals("companyID").(string)
+	role := c.Locals("role").(string)
+	reportID := c.Params("id")
+	expenseID := c.Params("expenseId")
+
+	if err := validator.ValidateObjectID(reportID); err != nil {
+		return response.BadRequest(c, "Invalid report ID")
+	}
+	if err := validator.ValidateObjectID(expenseID); err != nil {
+		return response.BadRequest(c, "Invalid expense ID")
+	}
+
+	var req service.RejectReportLineRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	report, err := h.reportService.RejectLine(c.Context(), companyID, userID, role, reportID, expenseID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Report expense rejected successfully", report)
+}
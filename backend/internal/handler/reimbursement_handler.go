@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/service"
+	"expensio-backend/pkg/response"
+	"expensio-backend/pkg/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReimbursementHandler struct {
+	reimbursementService *service.ReimbursementService
+	cfg                  *config.Config
+}
+
+// NewReimbursementHandler creates a new reimbursement handler
+func NewReimbursementHandler(reimbursementService *service.ReimbursementService, cfg *config.Config) *ReimbursementHandler {
+	return &ReimbursementHandler{
+		reimbursementService: reimbursementService,
+		cfg:                  cfg,
+	}
+}
+
+// CreateBatches schedules approved expenses in reimbursement batches (Admin only)
+// @route POST /api/v1/reimbursements/batches
+func (h *ReimbursementHandler) CreateBatches(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	userID := c.Locals("userID").(string)
+
+	var req service.CreateBatchesRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return response.BadRequest(c, "Invalid request body")
+		}
+	}
+
+	batches, err := h.reimbursementService.CreateBatches(c.Context(), companyID, userID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.Created(c, "Reimbursement batches created successfully", batches)
+}
+
+// GetBatches retrieves the company's reimbursement batches (Admin only)
+// @route GET /api/v1/reimbursements/batches
+func (h *ReimbursementHandler) GetBatches(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+
+	batches, err := h.reimbursementService.GetBatches(c.Context(), companyID)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to fetch reimbursement batches")
+	}
+
+	return response.OK(c, "Reimbursement batches retrieved successfully", batches)
+}
+
+// GetBatch retrieves a single reimbursement batch (Admin only)
+// @route GET /api/v1/reimbursements/batches/:id
+func (h *ReimbursementHandler) GetBatch(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	batchID := c.Params("id")
+
+	if err := validator.ValidateObjectID(batchID); err != nil {
+		return response.BadRequest(c, "Invalid batch ID")
+	}
+
+	batch, err := h.reimbursementService.GetBatch(c.Context(), companyID, batchID)
+	if err != nil {
+		return response.NotFound(c, "Reimbursement batch not found")
+	}
+
+	return response.OK(c, "Reimbursement batch retrieved successfully", batch)
+}
+
+// PayBatch records the payment of a batch and marks its expenses paid (Admin only)
+// @route POST /api/v1/reimbursements/batches/:id/pay
+func (h *ReimbursementHandler) PayBatch(c *fiber.Ctx) error {
+	companyID := c.Locals("companyID").(string)
+	userID := c.Locals("userID").(string)
+	batchID := c.Params("id")
+
+	if err := validator.ValidateObjectID(batchID); err != nil {
+		return response.BadRequest(c, "Invalid batch ID")
+	}
+
+	var req service.PayBatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	batch, err := h.reimbursementService.PayBatch(c.Context(), companyID, userID, batchID, &req)
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Reimbursement batch paid successfully", batch)
+}
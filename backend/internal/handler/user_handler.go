@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"strings"
+
 	"expensio-backend/internal/config"
 	"expensio-backend/internal/domain"
 	"expensio-backend/internal/service"
@@ -145,6 +147,54 @@ func (h *UserHandler) AssignManager(c *fiber.Ctx) error {
 	return response.OK(c, "Manager assigned successfully", nil)
 }
 
+// UpdateApprovalLimit sets or clears a user's approval limit (Admin only)
+// @route PUT /api/v1/users/:id/approval-limit
+func (h *UserHandler) UpdateApprovalLimit(c *fiber.Ctx) error {
+	userID := c.Params("id")
+
+	if err := validator.ValidateObjectID(userID); err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	var req struct {
+		ApprovalLimit *float64 `json:"approval_limit"` // null clears the limit
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	if err := h.userService.UpdateApprovalLimit(c.Context(), userID, req.ApprovalLimit); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Approval limit updated successfully", nil)
+}
+
+// UpdateDepartment sets or clears a user's department (Admin only)
+// @route PUT /api/v1/users/:id/department
+func (h *UserHandler) UpdateDepartment(c *fiber.Ctx) error {
+	userID := c.Params("id")
+
+	if err := validator.ValidateObjectID(userID); err != nil {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	var req struct {
+		Department string `json:"department"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	if err := h.userService.UpdateDepartment(c.Context(), userID, strings.TrimSpace(req.Department)); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Department updated successfully", nil)
+}
+
 // DeleteUser deletes a user (Admin only)
 // @route DELETE /api/v1/users/:id
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
@@ -160,3 +210,43 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 
 	return response.OK(c, "User deleted successfully", nil)
 }
+
+// GetDigestSettings retrieves the current user's daily digest settings
+// @route GET /api/v1/users/me/digest
+func (h *UserHandler) GetDigestSettings(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	settings, err := h.userService.GetDigestSettings(c.Context(), userID)
+	if err != nil {
+		return response.NotFound(c, "User not found")
+	}
+
+	return response.OK(c, "Digest settings retrieved successfully", settings)
+}
+
+// UpdateDigestSettings opts the current user in or out of the daily digest
+// @route PUT /api/v1/users/me/digest
+func (h *UserHandler) UpdateDigestSettings(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	var req struct {
+		Enabled  bool   `json:"enabled"`
+		SendTime string `json:"send_time"` // "HH:MM" in the time zone
+		Timezone string `json:"timezone"`  // IANA name, defaults to UTC
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+
+	settings, err := h.userService.UpdateDigestSettings(c.Context(), userID, &domain.DigestSettings{
+		Enabled:  req.Enabled,
+		SendTime: req.SendTime,
+		Timezone: req.Timezone,
+	})
+	if err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	return response.OK(c, "Digest settings updated successfully", settings)
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClientIPMiddleware records the client IP so that services can attribute
+// changes to where they came from
+func ClientIPMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("clientIP", c.IP())
+		return c.Next()
+	}
+}
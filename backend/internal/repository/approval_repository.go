@@ -119,7 +119,63 @@ func (r *approvalRepository) FindPendingByApproverID(ctx context.Context, approv
 	return approvals, nil
 }
 
-// FindPendingByApproverIDWithDetails returns pending approvals with populated expense and user data
+// FindOpenByApproverID returns the approver's approvals that are pending or
+// queued behind an earlier sequential level
+func (r *approvalRepository) FindOpenByApproverID(ctx context.Context, approverID string) ([]*domain.Approval, error) {
+	objectID, err := primitive.ObjectIDFromHex(approverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approver ID: %w", err)
+	}
+
+	filter := bson.M{
+		"approver_id": objectID,
+		"status": bson.M{
+			"$in": []domain.ApprovalStatus{domain.ApprovalPending, domain.ApprovalQueued},
+		},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find open approvals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var approvals []*domain.Approval
+	if err := cursor.All(ctx, &approvals); err != nil {
+		return nil, fmt.Errorf("failed to decode approvals: %w", err)
+	}
+
+	return approvals, nil
+}
+
+// FindPendingAssignedBefore returns pending approvals that were assigned
+// before the given time. Approvals without an assignment time fall back to
+// their creation time.
+func (r *approvalRepository) FindPendingAssignedBefore(ctx context.Context, before time.Time) ([]*domain.Approval, error) {
+	filter := bson.M{
+		"status": domain.ApprovalPending,
+		"$or": []bson.M{
+			{"assigned_at": bson.M{"$lte": before}},
+			{"assigned_at": bson.M{"$exists": false}, "created_at": bson.M{"$lte": before}},
+		},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stale approvals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var approvals []*domain.Approval
+	if err := cursor.All(ctx, &approvals); err != nil {
+		return nil, fmt.Errorf("failed to decode approvals: %w", err)
+	}
+
+	return approvals, nil
+}
+
+// FindPendingByApproverIDWithDetails returns pending approvals with populated expense and user data.
+// Approvals whose expense is no longer pending are left out.
 func (r *approvalRepository) FindPendingByApproverIDWithDetails(ctx context.Context, approverID string) ([]*domain.ApprovalWithDetails, error) {
 	fmt.Printf("🔍 FindPendingByApproverIDWithDetails - Looking for approver ID: %s\n", approverID)
 
@@ -129,15 +185,120 @@ func (r *approvalRepository) FindPendingByApproverIDWithDetails(ctx context.Cont
 		return nil, fmt.Errorf("invalid approver ID: %w", err)
 	}
 
-	// Build aggregation pipeline to join with expenses and users
-	pipeline := []bson.M{
-		// Match pending approvals for this approver
-		{
-			"$match": bson.M{
-				"approver_id": objectID,
-				"status":      domain.ApprovalPending,
-			},
+	pipeline := pendingDetailsPipeline(bson.M{
+		"$match": bson.M{
+			"approver_id": objectID,
+			"status":      domain.ApprovalPending,
 		},
+	})
+	// Keep approvals whose expense still waits for a decision
+	pipeline = append(pipeline, bson.M{
+		"$match": bson.M{"expense.status": domain.StatusPending},
+	})
+
+	fmt.Printf("🔍 Executing aggregation pipeline with %d stages\n", len(pipeline))
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		fmt.Printf("❌ Aggregation query failed: %v\n", err)
+		return nil, fmt.Errorf("failed to aggregate pending approvals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var approvals []*domain.ApprovalWithDetails
+	if err := cursor.All(ctx, &approvals); err != nil {
+		fmt.Printf("❌ Failed to decode approvals with details: %v\n", err)
+		return nil, fmt.Errorf("failed to decode approvals: %w", err)
+	}
+
+	fmt.Printf("✅ Found %d approval records with details\n", len(approvals))
+	for i, approval := range approvals {
+		expenseDesc := "nil"
+		userName := "nil"
+		if approval.Expense != nil {
+			expenseDesc = approval.Expense.Description
+			if approval.Expense.User != nil {
+				userName = approval.Expense.User.FirstName + " " + approval.Expense.User.LastName
+			}
+		}
+		fmt.Printf("   [%d] Approval ID: %s, Expense: %s, User: %s, Status: %s\n",
+			i+1, approval.ID.Hex(), expenseDesc, userName, approval.Status)
+	}
+
+	return approvals, nil
+}
+
+// FindPendingByApproverIDAfter returns up to limit of the approver's pending
+// approvals with details that come after the cursor, newest first by
+// (created_at, _id). A nil cursor starts at the newest. Approvals whose expense
+// is no longer pending are skipped, scanning on until the page is full.
+func (r *approvalRepository) FindPendingByApproverIDAfter(ctx context.Context, approverID string, after *domain.PageCursor, limit int) ([]*domain.ApprovalWithDetails, error) {
+	objectID, err := primitive.ObjectIDFromHex(approverID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approver ID: %w", err)
+	}
+
+	approvals := make([]*domain.ApprovalWithDetails, 0, limit)
+	for len(approvals) < limit {
+		match := bson.M{
+			"approver_id": objectID,
+			"status":      domain.ApprovalPending,
+		}
+		if after != nil {
+			match["$or"] = []bson.M{
+				{"created_at": bson.M{"$lt": after.CreatedAt}},
+				{"created_at": after.CreatedAt, "_id": bson.M{"$lt": after.ID}},
+			}
+		}
+
+		// Page on the approvals alone so the index serves the sort, then join
+		batch, err := r.aggregateDetails(ctx, pendingDetailsPipeline(
+			bson.M{"$match": match},
+			bson.M{"$sort": bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+			bson.M{"$limit": int64(limit)},
+		))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, approval := range batch {
+			if len(approvals) < limit && approval.Expense != nil && approval.Expense.Status == domain.StatusPending {
+				approvals = append(approvals, approval)
+			}
+		}
+		if len(batch) < limit {
+			break
+		}
+
+		last := batch[len(batch)-1]
+		after = &domain.PageCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return approvals, nil
+}
+
+// aggregateDetails runs a pipeline built by pendingDetailsPipeline
+func (r *approvalRepository) aggregateDetails(ctx context.Context, pipeline []bson.M) ([]*domain.ApprovalWithDetails, error) {
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate pending approvals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var approvals []*domain.ApprovalWithDetails
+	if err := cursor.All(ctx, &approvals); err != nil {
+		return nil, fmt.Errorf("failed to decode approvals: %w", err)
+	}
+
+	return approvals, nil
+}
+
+// pendingDetailsPipeline joins the approvals the head stages select with their
+// expense, its submitter and the approver
+func pendingDetailsPipeline(head ...bson.M) []bson.M {
+	pipeline := append([]bson.M{}, head...)
+
+	return append(pipeline, []bson.M{
 		// Lookup expense details
 		{
 			"$lookup": bson.M{
@@ -189,15 +350,23 @@ func (r *approvalRepository) FindPendingByApproverIDWithDetails(ctx context.Cont
 		// Project final structure
 		{
 			"$project": bson.M{
-				"_id":         1,
-				"expense_id":  1,
-				"approver_id": 1,
-				"level":       1,
-				"status":      1,
-				"comments":    1,
-				"approved_at": 1,
-				"created_at":  1,
-				"updated_at":  1,
+				"_id":               1,
+				"expense_id":        1,
+				"approver_id":       1,
+				"on_behalf_of_id":   1,
+				"delegation_id":     1,
+				"level":             1,
+				"round":             1,
+				"status":            1,
+				"comments":          1,
+				"approved_at":       1,
+				"assigned_at":       1,
+				"escalated_at":      1,
+				"escalated_to_id":   1,
+				"escalated_from_id": 1,
+				"escalation_reason": 1,
+				"created_at":        1,
+				"updated_at":        1,
 				"expense": bson.M{
 					"_id":                    "$expense_data._id",
 					"user_id":                "$expense_data.user_id",
@@ -220,54 +389,45 @@ func (r *approvalRepository) FindPendingByApproverIDWithDetails(ctx context.Cont
 				"approver": "$approver_data",
 			},
 		},
-	}
+	}...)
+}
 
-	fmt.Printf("🔍 Executing aggregation pipeline with %d stages\n", len(pipeline))
+func (r *approvalRepository) Update(ctx context.Context, approval *domain.Approval) error {
+	approval.UpdatedAt = time.Now()
 
-	cursor, err := r.collection.Aggregate(ctx, pipeline)
-	if err != nil {
-		fmt.Printf("❌ Aggregation query failed: %v\n", err)
-		return nil, fmt.Errorf("failed to aggregate pending approvals: %w", err)
+	update := bson.M{
+		"$set": approval,
 	}
-	defer cursor.Close(ctx)
 
-	var approvals []*domain.ApprovalWithDetails
-	if err := cursor.All(ctx, &approvals); err != nil {
-		fmt.Printf("❌ Failed to decode approvals with details: %v\n", err)
-		return nil, fmt.Errorf("failed to decode approvals: %w", err)
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": approval.ID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update approval: %w", err)
 	}
 
-	fmt.Printf("✅ Found %d approval records with details\n", len(approvals))
-	for i, approval := range approvals {
-		expenseDesc := "nil"
-		userName := "nil"
-		if approval.Expense != nil {
-			expenseDesc = approval.Expense.Description
-			if approval.Expense.User != nil {
-				userName = approval.Expense.User.FirstName + " " + approval.Expense.User.LastName
-			}
-		}
-		fmt.Printf("   [%d] Approval ID: %s, Expense: %s, User: %s, Status: %s\n",
-			i+1, approval.ID.Hex(), expenseDesc, userName, approval.Status)
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("approval not found")
 	}
 
-	return approvals, nil
+	return nil
 }
 
-func (r *approvalRepository) Update(ctx context.Context, approval *domain.Approval) error {
+// Transition writes the approval only if it is still in the from status, so
+// that a single decision wins each approval. It returns
+// domain.ErrConcurrentUpdate when the approval has moved on.
+func (r *approvalRepository) Transition(ctx context.Context, approval *domain.Approval, from domain.ApprovalStatus) error {
 	approval.UpdatedAt = time.Now()
 
 	update := bson.M{
 		"$set": approval,
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": approval.ID}, update)
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": approval.ID, "status": from}, update)
 	if err != nil {
 		return fmt.Errorf("failed to update approval: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("approval not found")
+		return domain.ErrConcurrentUpdate
 	}
 
 	return nil
@@ -300,6 +460,47 @@ func (r *approvalRepository) UpdateStatus(ctx context.Context, id string, status
 	return nil
 }
 
+// CancelOpenByExpenseID cancels the expense's pending and queued approvals
+func (r *approvalRepository) CancelOpenByExpenseID(ctx context.Context, expenseID string) error {
+	objectID, err := primitive.ObjectIDFromHex(expenseID)
+	if err != nil {
+		return fmt.Errorf("invalid expense ID: %w", err)
+	}
+
+	filter := bson.M{
+		"expense_id": objectID,
+		"status": bson.M{
+			"$in": []domain.ApprovalStatus{domain.ApprovalPending, domain.ApprovalQueued},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     domain.ApprovalCancelled,
+			"updated_at": time.Now(),
+		},
+	}
+
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to cancel approvals: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByExpenseID deletes every approval of the expense
+func (r *approvalRepository) DeleteByExpenseID(ctx context.Context, expenseID string) error {
+	objectID, err := primitive.ObjectIDFromHex(expenseID)
+	if err != nil {
+		return fmt.Errorf("invalid expense ID: %w", err)
+	}
+
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"expense_id": objectID}); err != nil {
+		return fmt.Errorf("failed to delete approvals: %w", err)
+	}
+
+	return nil
+}
+
 func (r *approvalRepository) CountApprovedByExpenseID(ctx context.Context, expenseID string) (int64, error) {
 	objectID, err := primitive.ObjectIDFromHex(expenseID)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type approvalRuleRepository struct {
@@ -55,32 +56,80 @@ func (r *approvalRuleRepository) FindByID(ctx context.Context, id string) (*doma
 	return &rule, nil
 }
 
-func (r *approvalRuleRepository) FindByCompanyID(ctx context.Context, companyID string) (*domain.ApprovalRule, error) {
+// FindActiveByCompanyID returns the company's active rules in matching order
+func (r *approvalRuleRepository) FindActiveByCompanyID(ctx context.Context, companyID string) ([]*domain.ApprovalRule, error) {
 	objectID, err := primitive.ObjectIDFromHex(companyID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid company ID: %w", err)
 	}
 
-	var rule domain.ApprovalRule
-	err = r.collection.FindOne(ctx, bson.M{
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{
 		"company_id": objectID,
 		"is_active":  true,
-	}).Decode(&rule)
+	}, opts)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("approval rule not found")
-		}
-		return nil, fmt.Errorf("failed to find approval rule: %w", err)
+		return nil, fmt.Errorf("failed to find approval rules: %w", err)
 	}
+	defer cursor.Close(ctx)
 
-	return &rule, nil
+	var rules []*domain.ApprovalRule
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode approval rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *approvalRuleRepository) FindAllByCompanyID(ctx context.Context, companyID string) ([]*domain.ApprovalRule, error) {
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"company_id": objectID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find approval rules: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rules []*domain.ApprovalRule
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode approval rules: %w", err)
+	}
+
+	return rules, nil
 }
 
 func (r *approvalRuleRepository) Update(ctx context.Context, rule *domain.ApprovalRule) error {
 	rule.UpdatedAt = time.Now()
 
+	// Set every field explicitly so that cleared optional fields are
+	// overwritten instead of being skipped by omitempty
 	update := bson.M{
-		"$set": rule,
+		"$set": bson.M{
+			"name":                 rule.Name,
+			"type":                 rule.Type,
+			"version":              rule.Version,
+			"priority":             rule.Priority,
+			"criteria":             rule.Criteria,
+			"is_default":           rule.IsDefault,
+			"sequential_approvers": rule.SequentialApprovers,
+			"percentage_required":  rule.PercentageRequired,
+			"specific_approver_id": rule.SpecificApproverID,
+			"minimum_approvals":    rule.MinimumApprovals,
+			"maximum_approvals":    rule.MaximumApprovals,
+			"allowed_approvers":    rule.AllowedApprovers,
+			"amount_thresholds":    rule.AmountThresholds,
+			"expression":           rule.Expression,
+			"manager_chain":        rule.ManagerChain,
+			"resubmission_policy":  rule.ResubmissionPolicy,
+			"is_active":            rule.IsActive,
+			"updated_at":           rule.UpdatedAt,
+		},
 	}
 
 	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": rule.ID}, update)
@@ -95,6 +144,62 @@ func (r *approvalRuleRepository) Update(ctx context.Context, rule *domain.Approv
 	return nil
 }
 
+func (r *approvalRuleRepository) SetActive(ctx context.Context, id string, isActive bool) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid approval rule ID: %w", err)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"is_active":  isActive,
+			"updated_at": time.Now(),
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update approval rule status: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("approval rule not found")
+	}
+
+	return nil
+}
+
+// ClearDefaultExcept unmarks every default rule of the company except keepID
+func (r *approvalRuleRepository) ClearDefaultExcept(ctx context.Context, companyID, keepID string) error {
+	companyObjectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return fmt.Errorf("invalid company ID: %w", err)
+	}
+
+	keepObjectID, err := primitive.ObjectIDFromHex(keepID)
+	if err != nil {
+		return fmt.Errorf("invalid approval rule ID: %w", err)
+	}
+
+	filter := bson.M{
+		"company_id": companyObjectID,
+		"_id":        bson.M{"$ne": keepObjectID},
+		"is_default": true,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"is_default": false,
+			"updated_at": time.Now(),
+		},
+	}
+
+	if _, err := r.collection.UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to clear default approval rules: %w", err)
+	}
+
+	return nil
+}
+
 func (r *approvalRuleRepository) Delete(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type auditRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository() domain.AuditRepository {
+	return &auditRepository{
+		collection: database.GetCollection("audit_entries"),
+	}
+}
+
+// Append inserts the entry as is. The unique index on company and sequence
+// makes it fail with domain.ErrConcurrentUpdate when another entry took the
+// sequence first.
+func (r *auditRepository) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	result, err := r.collection.InsertOne(ctx, entry)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrConcurrentUpdate
+		}
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindLastByCompanyID returns the head of the company's chain, or nil when
+// the company has no entries yet
+func (r *auditRepository) FindLastByCompanyID(ctx context.Context, companyID string) (*domain.AuditEntry, error) {
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}})
+
+	var entry domain.AuditEntry
+	err = r.collection.FindOne(ctx, bson.M{"company_id": objectID}, opts).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find audit entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// FindByCompanyID returns the company's chain in order
+func (r *auditRepository) FindByCompanyID(ctx context.Context, companyID string) ([]*domain.AuditEntry, error) {
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
+	}
+
+	return r.find(ctx, bson.M{"company_id": objectID})
+}
+
+// FindByEntityID returns the entries about one entity of the company in order
+func (r *auditRepository) FindByEntityID(ctx context.Context, companyID, entityID string) ([]*domain.AuditEntry, error) {
+	companyObjID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
+	}
+	entityObjID, err := primitive.ObjectIDFromHex(entityID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid entity ID: %w", err)
+	}
+
+	return r.find(ctx, bson.M{"company_id": companyObjID, "entity_id": entityObjID})
+}
+
+func (r *auditRepository) find(ctx context.Context, filter bson.M) ([]*domain.AuditEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find audit entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*domain.AuditEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode audit entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -78,6 +78,7 @@ func (r *companyRepository) Update(ctx context.Context, company *domain.Company)
 			"country":          company.Country,
 			"admin_user_id":    company.AdminUserID,
 			"approval_rule_id": company.ApprovalRuleID,
+			"settings":         company.Settings,
 			"is_active":        company.IsActive,
 			"updated_at":       company.UpdatedAt,
 		},
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type delegationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewDelegationRepository creates a new delegation repository
+func NewDelegationRepository() domain.DelegationRepository {
+	return &delegationRepository{
+		collection: database.GetCollection("delegations"),
+	}
+}
+
+func (r *delegationRepository) Create(ctx context.Context, delegation *domain.Delegation) error {
+	delegation.CreatedAt = time.Now()
+	delegation.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, delegation)
+	if err != nil {
+		return fmt.Errorf("failed to create delegation: %w", err)
+	}
+
+	delegation.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *delegationRepository) FindByID(ctx context.Context, id string) (*domain.Delegation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delegation ID: %w", err)
+	}
+
+	var delegation domain.Delegation
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&delegation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("delegation not found")
+		}
+		return nil, fmt.Errorf("failed to find delegation: %w", err)
+	}
+
+	return &delegation, nil
+}
+
+func (r *delegationRepository) FindByCompanyID(ctx context.Context, companyID string) ([]*domain.Delegation, error) {
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
+	}
+
+	return r.find(ctx, bson.M{"company_id": objectID})
+}
+
+// FindByUserID returns delegations where the user is either delegator or delegate
+func (r *delegationRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.Delegation, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	return r.find(ctx, bson.M{
+		"$or": []bson.M{
+			{"delegator_id": objectID},
+			{"delegate_id": objectID},
+		},
+	})
+}
+
+// FindActiveByDelegatorID returns the delegator's delegations in effect at the given time, oldest first
+func (r *delegationRepository) FindActiveByDelegatorID(ctx context.Context, delegatorID string, at time.Time) ([]*domain.Delegation, error) {
+	objectID, err := primitive.ObjectIDFromHex(delegatorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delegator ID: %w", err)
+	}
+
+	filter := bson.M{
+		"delegator_id": objectID,
+		"is_active":    true,
+		"start_date":   bson.M{"$lte": at},
+		"end_date":     bson.M{"$gt": at},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active delegations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var delegations []*domain.Delegation
+	if err := cursor.All(ctx, &delegations); err != nil {
+		return nil, fmt.Errorf("failed to decode delegations: %w", err)
+	}
+
+	return delegations, nil
+}
+
+func (r *delegationRepository) Update(ctx context.Context, delegation *domain.Delegation) error {
+	delegation.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"delegate_id": delegation.DelegateID,
+			"start_date":  delegation.StartDate,
+			"end_date":    delegation.EndDate,
+			"categories":  delegation.Categories,
+			"max_amount":  delegation.MaxAmount,
+			"reason":      delegation.Reason,
+			"is_active":   delegation.IsActive,
+			"updated_at":  delegation.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": delegation.ID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update delegation: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("delegation not found")
+	}
+
+	return nil
+}
+
+// find returns delegations matching filter, newest first
+func (r *delegationRepository) find(ctx context.Context, filter bson.M) ([]*domain.Delegation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "start_date", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find delegations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var delegations []*domain.Delegation
+	if err := cursor.All(ctx, &delegations); err != nil {
+		return nil, fmt.Errorf("failed to decode delegations: %w", err)
+	}
+
+	return delegations, nil
+}
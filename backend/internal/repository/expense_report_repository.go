@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type expenseReportRepository struct {
+	collection *mongo.Collection
+}
+
+// NewExpenseReportRepository creates a new expense report repository
+func NewExpenseReportRepository() domain.ExpenseReportRepository {
+	return &expenseReportRepository{
+		collection: database.GetCollection("expense_reports"),
+	}
+}
+
+func (r *expenseReportRepository) Create(ctx context.Context, report *domain.ExpenseReport) error {
+	report.CreatedAt = time.Now()
+	report.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, report)
+	if err != nil {
+		return fmt.Errorf("failed to create expense report: %w", err)
+	}
+
+	report.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *expenseReportRepository) FindByID(ctx context.Context, id string) (*domain.ExpenseReport, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report ID: %w", err)
+	}
+
+	var report domain.ExpenseReport
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&report)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("expense report not found")
+		}
+		return nil, fmt.Errorf("failed to find expense report: %w", err)
+	}
+
+	return &report, nil
+}
+
+func (r *expenseReportRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.ExpenseReport, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	return r.find(ctx, bson.M{"user_id": objectID})
+}
+
+// FindByCompanyID returns the company's submitted reports, drafts stay
+// private to their owner
+func (r *expenseReportRepository) FindByCompanyID(ctx context.Context, companyID string) ([]*domain.ExpenseReport, error) {
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
+	}
+
+	return r.find(ctx, bson.M{"company_id": objectID, "status": bson.M{"$ne": domain.ReportDraft}})
+}
+
+// find returns the reports matching filter, newest first
+func (r *expenseReportRepository) find(ctx context.Context, filter bson.M) ([]*domain.ExpenseReport, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expense reports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reports []*domain.ExpenseReport
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, fmt.Errorf("failed to decode expense reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// Update writes the report if nobody else wrote it since it was read, and
+// returns domain.ErrConcurrentUpdate otherwise
+func (r *expenseReportRepository) Update(ctx context.Context, report *domain.ExpenseReport) error {
+	filter := bson.M{"_id": report.ID, "version": report.Version}
+
+	report.UpdatedAt = time.Now()
+	report.Version++
+
+	result, err := r.collection.ReplaceOne(ctx, filter, report)
+	if err != nil {
+		report.Version--
+		return fmt.Errorf("failed to update expense report: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		report.Version--
+		return domain.ErrConcurrentUpdate
+	}
+
+	return nil
+}
+
+func (r *expenseReportRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid report ID: %w", err)
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return fmt.Errorf("failed to delete expense report: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("expense report not found")
+	}
+
+	return nil
+}
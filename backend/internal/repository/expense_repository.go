@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"time"
 
 	"expensio-backend/internal/domain"
@@ -56,53 +57,123 @@ func (r *expenseRepository) FindByID(ctx context.Context, id string) (*domain.Ex
 	return &expense, nil
 }
 
-func (r *expenseRepository) FindByUserID(ctx context.Context, userID string, page, limit int) ([]*domain.Expense, int64, error) {
+func (r *expenseRepository) FindByUserID(ctx context.Context, userID string, filter *domain.ExpenseFilter, page, limit int) ([]*domain.Expense, int64, error) {
 	objectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return nil, 0, fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	filter := bson.M{"user_id": objectID}
+	return r.findPage(ctx, userExpenseQuery(objectID), filter, page, limit)
+}
 
-	// Count total documents
-	total, err := r.collection.CountDocuments(ctx, filter)
+func (r *expenseRepository) FindByCompanyID(ctx context.Context, companyID string, filter *domain.ExpenseFilter, page, limit int) ([]*domain.Expense, int64, error) {
+	query, err := companyExpenseQuery(companyID, filter)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count expenses: %w", err)
+		return nil, 0, err
 	}
 
-	// Calculate skip
-	skip := int64((page - 1) * limit)
+	return r.findPage(ctx, query, filter, page, limit)
+}
+
+func (r *expenseRepository) FindByUserIDAfter(ctx context.Context, userID string, filter *domain.ExpenseFilter, after *domain.PageCursor, limit int) ([]*domain.Expense, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	return r.findAfter(ctx, userExpenseQuery(objectID), filter, after, limit)
+}
+
+func (r *expenseRepository) FindByCompanyIDAfter(ctx context.Context, companyID string, filter *domain.ExpenseFilter, after *domain.PageCursor, limit int) ([]*domain.Expense, error) {
+	query, err := companyExpenseQuery(companyID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.findAfter(ctx, query, filter, after, limit)
+}
+
+// withoutRoutingExpenses leaves the expenses that carry a report through
+// approval out of query. Lists show them as the report instead, and they are
+// never reimbursed themselves.
+func withoutRoutingExpenses(query bson.M) bson.M {
+	query["routed_report_id"] = bson.M{"$exists": false}
+	return query
+}
+
+// userExpenseQuery scopes a list to the user's own expenses
+func userExpenseQuery(userID primitive.ObjectID) bson.M {
+	return withoutRoutingExpenses(bson.M{"user_id": userID})
+}
+
+// companyExpenseQuery scopes a list to the company and the filtered submitter
+func companyExpenseQuery(companyID string, filter *domain.ExpenseFilter) (bson.M, error) {
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
+	}
+
+	// Drafts stay private to their owner
+	query := withoutRoutingExpenses(bson.M{
+		"company_id": objectID,
+		"status":     bson.M{"$ne": domain.StatusDraft},
+	})
+	if filter != nil && filter.SubmitterID != "" {
+		submitterID, err := primitive.ObjectIDFromHex(filter.SubmitterID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid submitter ID: %w", err)
+		}
+		query["user_id"] = submitterID
+	}
+
+	return query, nil
+}
+
+// findAfter returns up to limit expenses matching query and filter that come
+// after the cursor in (created_at, _id) order. Unlike skipping, inserts made
+// meanwhile do not shift the following pages.
+func (r *expenseRepository) findAfter(ctx context.Context, query bson.M, filter *domain.ExpenseFilter, after *domain.PageCursor, limit int) ([]*domain.Expense, error) {
+	if filter != nil {
+		applyExpenseFilter(query, filter)
+	}
+
+	if after != nil {
+		beyond := "$lt"
+		if filter != nil && filter.SortAsc {
+			beyond = "$gt"
+		}
+		query["$or"] = []bson.M{
+			{"created_at": bson.M{beyond: after.CreatedAt}},
+			{"created_at": after.CreatedAt, "_id": bson.M{beyond: after.ID}},
+		}
+	}
 
-	// Find with pagination
 	opts := options.Find().
-		SetSort(bson.D{{Key: "created_at", Value: -1}}).
-		SetSkip(skip).
+		SetSort(expenseSort(filter)).
 		SetLimit(int64(limit))
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.collection.Find(ctx, query, opts)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to find expenses: %w", err)
+		return nil, fmt.Errorf("failed to find expenses: %w", err)
 	}
 	defer cursor.Close(ctx)
 
 	var expenses []*domain.Expense
 	if err := cursor.All(ctx, &expenses); err != nil {
-		return nil, 0, fmt.Errorf("failed to decode expenses: %w", err)
+		return nil, fmt.Errorf("failed to decode expenses: %w", err)
 	}
 
-	return expenses, total, nil
+	return expenses, nil
 }
 
-func (r *expenseRepository) FindByCompanyID(ctx context.Context, companyID string, page, limit int) ([]*domain.Expense, int64, error) {
-	objectID, err := primitive.ObjectIDFromHex(companyID)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid company ID: %w", err)
+// findPage returns one page of the expenses matching query and filter
+func (r *expenseRepository) findPage(ctx context.Context, query bson.M, filter *domain.ExpenseFilter, page, limit int) ([]*domain.Expense, int64, error) {
+	if filter != nil {
+		applyExpenseFilter(query, filter)
 	}
 
-	filter := bson.M{"company_id": objectID}
-
 	// Count total documents
-	total, err := r.collection.CountDocuments(ctx, filter)
+	total, err := r.collection.CountDocuments(ctx, query)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count expenses: %w", err)
 	}
@@ -112,11 +183,11 @@ func (r *expenseRepository) FindByCompanyID(ctx context.Context, companyID strin
 
 	// Find with pagination
 	opts := options.Find().
-		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSort(expenseSort(filter)).
 		SetSkip(skip).
 		SetLimit(int64(limit))
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	cursor, err := r.collection.Find(ctx, query, opts)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to find expenses: %w", err)
 	}
@@ -130,22 +201,157 @@ func (r *expenseRepository) FindByCompanyID(ctx context.Context, companyID strin
 	return expenses, total, nil
 }
 
+// applyExpenseFilter adds the filter's conditions to query
+func applyExpenseFilter(query bson.M, filter *domain.ExpenseFilter) {
+	if len(filter.Statuses) > 0 {
+		// Keeps a condition the scope already set on the status
+		status, ok := query["status"].(bson.M)
+		if !ok {
+			status = bson.M{}
+		}
+		status["$in"] = filter.Statuses
+		query["status"] = status
+	}
+	if len(filter.Categories) > 0 {
+		// Itemized expenses match on any of their lines
+		query["$and"] = []bson.M{{"$or": []bson.M{
+			{"category": bson.M{"$in": filter.Categories}},
+			{"line_items.category": bson.M{"$in": filter.Categories}},
+		}}}
+	}
+
+	if filter.DateFrom != nil || filter.DateTo != nil {
+		dateRange := bson.M{}
+		if filter.DateFrom != nil {
+			dateRange["$gte"] = *filter.DateFrom
+		}
+		if filter.DateTo != nil {
+			dateRange["$lte"] = *filter.DateTo
+		}
+		query["expense_date"] = dateRange
+	}
+
+	if filter.MinAmount != nil || filter.MaxAmount != nil {
+		amountRange := bson.M{}
+		if filter.MinAmount != nil {
+			amountRange["$gte"] = *filter.MinAmount
+		}
+		if filter.MaxAmount != nil {
+			amountRange["$lte"] = *filter.MaxAmount
+		}
+		query["converted_amount"] = amountRange
+	}
+
+	if filter.Currency != "" {
+		query["currency"] = filter.Currency
+	}
+	if filter.Merchant != "" {
+		query["merchant"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(filter.Merchant) + "$", Options: "i"}
+	}
+
+	// Served by the text index on description and merchant
+	if filter.Search != "" {
+		query["$text"] = bson.M{"$search": filter.Search}
+	}
+}
+
+// expenseSortFields maps the sortable fields to their document keys
+var expenseSortFields = map[string]string{
+	"created_at":   "created_at",
+	"expense_date": "expense_date",
+	"amount":       "converted_amount",
+	"merchant":     "merchant",
+	"category":     "category",
+	"status":       "status",
+}
+
+// expenseSort orders by the filter's sort field, newest first by default. The
+// id breaks ties so that pages do not overlap.
+func expenseSort(filter *domain.ExpenseFilter) bson.D {
+	key, direction := "created_at", -1
+	if filter != nil {
+		if field, ok := expenseSortFields[filter.SortBy]; ok {
+			key = field
+		}
+		if filter.SortAsc {
+			direction = 1
+		}
+	}
+
+	return bson.D{{Key: key, Value: direction}, {Key: "_id", Value: direction}}
+}
+
+// Update writes the expense if nobody else wrote it since it was read, and
+// returns domain.ErrConcurrentUpdate otherwise, or domain.ErrNotFound when
+// the expense was deleted
 func (r *expenseRepository) Update(ctx context.Context, expense *domain.Expense) error {
+	filter := bson.M{"_id": expense.ID, "version": versionFilter(expense.Version)}
+
 	expense.UpdatedAt = time.Now()
+	expense.Version++
 
 	update := bson.M{
 		"$set": expense,
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": expense.ID}, update)
+	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
+		expense.Version--
 		return fmt.Errorf("failed to update expense: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("expense not found")
+		expense.Version--
+		return r.writeConflict(ctx, expense.ID)
+	}
+
+	return nil
+}
+
+// writeConflict explains a conditional write on the expense that matched
+// nothing: domain.ErrNotFound when it no longer exists, and
+// domain.ErrConcurrentUpdate otherwise
+func (r *expenseRepository) writeConflict(ctx context.Context, id primitive.ObjectID) error {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+	if err == nil && count == 0 {
+		return domain.ErrNotFound
+	}
+	return domain.ErrConcurrentUpdate
+}
+
+// StartApprovalRound clears the expense's routing so it can be initialized again
+// under the expense's new approval round. It fails with domain.ErrConcurrentUpdate
+// when the expense was written since it was read, and domain.ErrNotFound when
+// it was deleted.
+func (r *expenseRepository) StartApprovalRound(ctx context.Context, expense *domain.Expense) error {
+	filter := bson.M{"_id": expense.ID, "version": versionFilter(expense.Version)}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"approval_round":         expense.ApprovalRound,
+			"current_approval_level": 0,
+			"updated_at":             now,
+		},
+		"$unset": bson.M{
+			"approval_threshold": "",
+			"approval_rule_id":   "",
+			"approval_rule":      "",
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to reset expense routing: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return r.writeConflict(ctx, expense.ID)
 	}
 
+	expense.UpdatedAt = now
+	expense.Version++
 	return nil
 }
 
@@ -167,42 +373,106 @@ func (r *expenseRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *expenseRepository) UpdateStatus(ctx context.Context, id string, status domain.ExpenseStatus) error {
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return fmt.Errorf("invalid expense ID: %w", err)
-	}
+// TransitionStatus moves the expense from one status to another in a single
+// atomic write and refreshes expense with the stored document. It returns
+// domain.ErrConcurrentUpdate when the expense is no longer in the from status,
+// and domain.ErrNotFound when it was deleted.
+func (r *expenseRepository) TransitionStatus(ctx context.Context, expense *domain.Expense, from, to domain.ExpenseStatus) error {
+	filter := bson.M{"_id": expense.ID, "status": from}
 
 	update := bson.M{
 		"$set": bson.M{
-			"status":     status,
+			"status":     to,
 			"updated_at": time.Now(),
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var updated domain.Expense
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return r.writeConflict(ctx, expense.ID)
+		}
 		return fmt.Errorf("failed to update expense status: %w", err)
 	}
 
-	if result.MatchedCount == 0 {
-		return fmt.Errorf("expense not found")
+	*expense = updated
+	return nil
+}
+
+// SubmitDraft writes the draft's fields and moves it to pending in a single
+// conditional write. It returns domain.ErrConcurrentUpdate when the expense
+// was written since it was read or is no longer a draft, and
+// domain.ErrNotFound when it was deleted.
+func (r *expenseRepository) SubmitDraft(ctx context.Context, expense *domain.Expense) error {
+	filter := bson.M{
+		"_id":     expense.ID,
+		"version": versionFilter(expense.Version),
+		"status":  domain.StatusDraft,
+	}
+
+	expense.Status = domain.StatusPending
+	expense.UpdatedAt = time.Now()
+	expense.Version++
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": expense})
+	if err == nil && result.MatchedCount == 0 {
+		err = r.writeConflict(ctx, expense.ID)
+	} else if err != nil {
+		err = fmt.Errorf("failed to submit expense: %w", err)
+	}
+	if err != nil {
+		expense.Status = domain.StatusDraft
+		expense.Version--
+		return err
 	}
 
 	return nil
 }
 
+// versionFilter matches the stored version of an expense, where expenses
+// written before versioning have none
+func versionFilter(version int) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
 func (r *expenseRepository) FindPendingByCompanyID(ctx context.Context, companyID string) ([]*domain.Expense, error) {
 	objectID, err := primitive.ObjectIDFromHex(companyID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid company ID: %w", err)
 	}
 
-	filter := bson.M{
+	// Members of a report are decided with the report, not on their own
+	return r.findPending(ctx, withoutRoutingExpenses(bson.M{
 		"company_id": objectID,
 		"status":     domain.StatusPending,
+		"report_id":  bson.M{"$exists": false},
+	}))
+}
+
+// FindPendingRoutingByCompanyID returns the expenses that carry the
+// company's pending expense reports through approval
+func (r *expenseRepository) FindPendingRoutingByCompanyID(ctx context.Context, companyID string) ([]*domain.Expense, error) {
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
 	}
 
+	return r.findPending(ctx, bson.M{
+		"company_id":       objectID,
+		"status":           domain.StatusPending,
+		"routed_report_id": bson.M{"$exists": true},
+	})
+}
+
+// findPending returns the expenses matching filter, newest first
+func (r *expenseRepository) findPending(ctx context.Context, filter bson.M) ([]*domain.Expense, error) {
 	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
@@ -218,3 +488,239 @@ func (r *expenseRepository) FindPendingByCompanyID(ctx context.Context, companyI
 
 	return expenses, nil
 }
+
+// FindApprovedUnbatchedByCompanyID returns the company's approved expenses
+// that no reimbursement batch holds yet, oldest first
+func (r *expenseRepository) FindApprovedUnbatchedByCompanyID(ctx context.Context, companyID string) ([]*domain.Expense, error) {
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
+	}
+
+	filter := withoutRoutingExpenses(bson.M{
+		"company_id":             objectID,
+		"status":                 domain.StatusApproved,
+		"reimbursement_batch_id": bson.M{"$exists": false},
+	})
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find approved expenses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var expenses []*domain.Expense
+	if err := cursor.All(ctx, &expenses); err != nil {
+		return nil, fmt.Errorf("failed to decode expenses: %w", err)
+	}
+
+	return expenses, nil
+}
+
+// AssignToBatch schedules an approved expense for reimbursement in the batch.
+// It returns domain.ErrConcurrentUpdate when the expense is no longer approved
+// or another batch took it first.
+func (r *expenseRepository) AssignToBatch(ctx context.Context, expense *domain.Expense, batchID string) error {
+	batchObjID, err := primitive.ObjectIDFromHex(batchID)
+	if err != nil {
+		return fmt.Errorf("invalid batch ID: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":                    expense.ID,
+		"status":                 domain.StatusApproved,
+		"reimbursement_batch_id": bson.M{"$exists": false},
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"status":                 domain.StatusScheduled,
+			"reimbursement_batch_id": batchObjID,
+			"updated_at":             now,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to schedule expense: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrConcurrentUpdate
+	}
+
+	expense.Status = domain.StatusScheduled
+	expense.ReimbursementBatchID = &batchObjID
+	expense.UpdatedAt = now
+	expense.Version++
+	return nil
+}
+
+// MarkBatchPaid moves the batch's scheduled expenses to paid and returns how
+// many moved
+func (r *expenseRepository) MarkBatchPaid(ctx context.Context, batchID string) (int64, error) {
+	batchObjID, err := primitive.ObjectIDFromHex(batchID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid batch ID: %w", err)
+	}
+
+	filter := bson.M{
+		"reimbursement_batch_id": batchObjID,
+		"status":                 domain.StatusScheduled,
+	}
+	update := bson.M{
+		"$set": bson.M{"status": domain.StatusPaid, "updated_at": time.Now()},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark expenses as paid: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// FindStaleDrafts returns the drafts created before createdBefore whose owner
+// was not reminded of them yet
+func (r *expenseRepository) FindStaleDrafts(ctx context.Context, createdBefore time.Time) ([]*domain.Expense, error) {
+	filter := bson.M{
+		"status":           domain.StatusDraft,
+		"created_at":       bson.M{"$lt": createdBefore},
+		"draft_flagged_at": bson.M{"$exists": false},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stale drafts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var expenses []*domain.Expense
+	if err := cursor.All(ctx, &expenses); err != nil {
+		return nil, fmt.Errorf("failed to decode expenses: %w", err)
+	}
+
+	return expenses, nil
+}
+
+// FlagStaleDraft records that the owner was reminded of the draft. It returns
+// false when the draft was flagged or submitted meanwhile.
+func (r *expenseRepository) FlagStaleDraft(ctx context.Context, expense *domain.Expense) (bool, error) {
+	filter := bson.M{
+		"_id":              expense.ID,
+		"status":           domain.StatusDraft,
+		"draft_flagged_at": bson.M{"$exists": false},
+	}
+
+	now := time.Now()
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"draft_flagged_at": now}})
+	if err != nil {
+		return false, fmt.Errorf("failed to flag draft: %w", err)
+	}
+	if result.ModifiedCount == 0 {
+		return false, nil
+	}
+
+	expense.DraftFlaggedAt = &now
+	return true, nil
+}
+
+// FindByReportID returns the member expenses of the report, oldest first
+func (r *expenseRepository) FindByReportID(ctx context.Context, reportID string) ([]*domain.Expense, error) {
+	objectID, err := primitive.ObjectIDFromHex(reportID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report ID: %w", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "expense_date", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"report_id": objectID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find report expenses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var expenses []*domain.Expense
+	if err := cursor.All(ctx, &expenses); err != nil {
+		return nil, fmt.Errorf("failed to decode expenses: %w", err)
+	}
+
+	return expenses, nil
+}
+
+// AssignToReport adds a draft to the report. It returns
+// domain.ErrConcurrentUpdate when the expense is no longer a draft or another
+// report took it first.
+func (r *expenseRepository) AssignToReport(ctx context.Context, expense *domain.Expense, reportID string) error {
+	reportObjID, err := primitive.ObjectIDFromHex(reportID)
+	if err != nil {
+		return fmt.Errorf("invalid report ID: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":       expense.ID,
+		"status":    domain.StatusDraft,
+		"report_id": bson.M{"$exists": false},
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"report_id":  reportObjID,
+			"updated_at": now,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to add expense to report: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrConcurrentUpdate
+	}
+
+	expense.ReportID = &reportObjID
+	expense.UpdatedAt = now
+	expense.Version++
+	return nil
+}
+
+// RemoveFromReport takes the expense out of its report and returns it to
+// draft. It returns domain.ErrConcurrentUpdate when the expense was submitted
+// with the report meanwhile.
+func (r *expenseRepository) RemoveFromReport(ctx context.Context, expense *domain.Expense) error {
+	filter := bson.M{
+		"_id":       expense.ID,
+		"report_id": expense.ReportID,
+		"status":    bson.M{"$in": bson.A{domain.StatusDraft, domain.StatusChangesRequested}},
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set":   bson.M{"status": domain.StatusDraft, "updated_at": now},
+		"$unset": bson.M{"report_id": ""},
+		"$inc":   bson.M{"version": 1},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to remove expense from report: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrConcurrentUpdate
+	}
+
+	expense.ReportID = nil
+	expense.Status = domain.StatusDraft
+	expense.UpdatedAt = now
+	expense.Version++
+	return nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type reimbursementBatchRepository struct {
+	collection *mongo.Collection
+}
+
+// NewReimbursementBatchRepository creates a new reimbursement batch repository
+func NewReimbursementBatchRepository() domain.ReimbursementBatchRepository {
+	return &reimbursementBatchRepository{
+		collection: database.GetCollection("reimbursement_batches"),
+	}
+}
+
+func (r *reimbursementBatchRepository) Create(ctx context.Context, batch *domain.ReimbursementBatch) error {
+	batch.CreatedAt = time.Now()
+	batch.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, batch)
+	if err != nil {
+		return fmt.Errorf("failed to create reimbursement batch: %w", err)
+	}
+
+	batch.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *reimbursementBatchRepository) FindByID(ctx context.Context, id string) (*domain.ReimbursementBatch, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid batch ID: %w", err)
+	}
+
+	var batch domain.ReimbursementBatch
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&batch)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("reimbursement batch not found")
+		}
+		return nil, fmt.Errorf("failed to find reimbursement batch: %w", err)
+	}
+
+	return &batch, nil
+}
+
+func (r *reimbursementBatchRepository) FindByCompanyID(ctx context.Context, companyID string) ([]*domain.ReimbursementBatch, error) {
+	objectID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID: %w", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"company_id": objectID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find reimbursement batches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var batches []*domain.ReimbursementBatch
+	if err := cursor.All(ctx, &batches); err != nil {
+		return nil, fmt.Errorf("failed to decode reimbursement batches: %w", err)
+	}
+
+	return batches, nil
+}
+
+// MarkPaid records the payment on a scheduled batch. It returns
+// domain.ErrConcurrentUpdate when the batch was already paid.
+func (r *reimbursementBatchRepository) MarkPaid(ctx context.Context, batch *domain.ReimbursementBatch) error {
+	batch.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":            domain.BatchPaid,
+			"payment_date":      batch.PaymentDate,
+			"payment_reference": batch.PaymentReference,
+			"paid_by":           batch.PaidBy,
+			"updated_at":        batch.UpdatedAt,
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": batch.ID, "status": domain.BatchScheduled}, update)
+	if err != nil {
+		return fmt.Errorf("failed to mark reimbursement batch as paid: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrConcurrentUpdate
+	}
+
+	batch.Status = domain.BatchPaid
+	return nil
+}
@@ -93,12 +93,14 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 
 	update := bson.M{
 		"$set": bson.M{
-			"first_name": user.FirstName,
-			"last_name":  user.LastName,
-			"role":       user.Role,
-			"manager_id": user.ManagerID,
-			"is_active":  user.IsActive,
-			"updated_at": user.UpdatedAt,
+			"first_name":     user.FirstName,
+			"last_name":      user.LastName,
+			"role":           user.Role,
+			"manager_id":     user.ManagerID,
+			"approval_limit": user.ApprovalLimit,
+			"department":     user.Department,
+			"is_active":      user.IsActive,
+			"updated_at":     user.UpdatedAt,
 		},
 	}
 
@@ -186,3 +188,120 @@ func (r *userRepository) AssignManager(ctx context.Context, userID, managerID st
 
 	return nil
 }
+
+func (r *userRepository) UpdateApprovalLimit(ctx context.Context, id string, limit *float64) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"approval_limit": limit,
+			"updated_at":     time.Now(),
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update approval limit: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateDigestSettings(ctx context.Context, id string, settings *domain.DigestSettings) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	// The last sent date is kept so that changing the time does not send twice a day
+	update := bson.M{
+		"$set": bson.M{
+			"digest.enabled":   settings.Enabled,
+			"digest.send_time": settings.SendTime,
+			"digest.timezone":  settings.Timezone,
+			"updated_at":       time.Now(),
+		},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update digest settings: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// FindDigestSubscribers returns the active users who opted in to the digest
+func (r *userRepository) FindDigestSubscribers(ctx context.Context) ([]*domain.User, error) {
+	filter := bson.M{
+		"is_active":      true,
+		"digest.enabled": true,
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find digest subscribers: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []*domain.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+
+	return users, nil
+}
+
+// ClaimDigest marks the user's digest of localDate as sent. It returns false
+// when it already was, so that each digest goes out once.
+func (r *userRepository) ClaimDigest(ctx context.Context, id, localDate string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":                 objectID,
+		"digest.last_sent_on": bson.M{"$ne": localDate},
+	}
+	update := bson.M{"$set": bson.M{"digest.last_sent_on": localDate}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest: %w", err)
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// ReleaseDigest undoes the claim on the user's digest of localDate when it
+// could not be sent, so that the next run tries again
+func (r *userRepository) ReleaseDigest(ctx context.Context, id, localDate, previous string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":                 objectID,
+		"digest.last_sent_on": localDate,
+	}
+	update := bson.M{"$set": bson.M{"digest.last_sent_on": previous}}
+
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to release digest: %w", err)
+	}
+
+	return nil
+}
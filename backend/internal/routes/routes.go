@@ -6,13 +6,21 @@ import (
 	"expensio-backend/internal/middleware"
 	"expensio-backend/internal/repository"
 	"expensio-backend/internal/service"
+	"expensio-backend/pkg/notifier"
 	"expensio-backend/pkg/ocr"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// Services exposes the services that background workers need
+type Services struct {
+	Approval *service.ApprovalService
+	Digest   *service.DigestService
+	Expense  *service.ExpenseService
+}
+
 // SetupRoutes configures all application routes
-func SetupRoutes(app *fiber.App, cfg *config.Config) {
+func SetupRoutes(app *fiber.App, cfg *config.Config) *Services {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository()
 	companyRepo := repository.NewCompanyRepository()
@@ -20,26 +28,47 @@ func SetupRoutes(app *fiber.App, cfg *config.Config) {
 	approvalRepo := repository.NewApprovalRepository()
 	approvalRuleRepo := repository.NewApprovalRuleRepository()
 	ocrResultRepo := repository.NewOCRResultRepository()
+	delegationRepo := repository.NewDelegationRepository()
+	auditRepo := repository.NewAuditRepository()
+	batchRepo := repository.NewReimbursementBatchRepository()
+	reportRepo := repository.NewExpenseReportRepository()
+
+	// Notifications go out by mail when SMTP is configured
+	userNotifier := notifier.NewNotifier(cfg)
 
 	// Initialize services
+	auditService := service.NewAuditService(auditRepo, cfg)
 	authService := service.NewAuthService(userRepo, companyRepo, cfg)
-	userService := service.NewUserService(userRepo, companyRepo, cfg)
-	expenseService := service.NewExpenseService(expenseRepo, userRepo, companyRepo, cfg)
-	approvalService := service.NewApprovalService(approvalRepo, approvalRuleRepo, expenseRepo, userRepo, cfg)
+	userService := service.NewUserService(userRepo, companyRepo, auditService, cfg)
+	expenseService := service.NewExpenseService(expenseRepo, userRepo, companyRepo, auditService, userNotifier, cfg)
+	approvalService := service.NewApprovalService(approvalRepo, approvalRuleRepo, expenseRepo, userRepo, companyRepo, delegationRepo, auditService, userNotifier, cfg)
+	approvalRuleService := service.NewApprovalRuleService(approvalRuleRepo, userRepo, companyRepo, auditService, cfg)
+	delegationService := service.NewDelegationService(delegationRepo, userRepo, approvalService, cfg)
+	companyService := service.NewCompanyService(companyRepo, userRepo, cfg)
+	reimbursementService := service.NewReimbursementService(batchRepo, expenseRepo, userRepo, auditService, cfg)
+	reportService := service.NewExpenseReportService(reportRepo, expenseRepo, approvalRepo, userRepo, companyRepo, approvalService, auditService, cfg)
+	digestService := service.NewDigestService(approvalRepo, userRepo, companyRepo, userNotifier, cfg)
 	ocrService := ocr.NewOCRService(cfg)
 
 	// Set approval service in expense service (to avoid circular dependency)
 	expenseService.SetApprovalService(approvalService)
+	approvalService.SetReportService(reportService)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService, cfg)
 	userHandler := handler.NewUserHandler(userService, cfg)
 	expenseHandler := handler.NewExpenseHandler(expenseService, cfg)
 	approvalHandler := handler.NewApprovalHandler(approvalService, cfg)
+	approvalRuleHandler := handler.NewApprovalRuleHandler(approvalRuleService, cfg)
+	delegationHandler := handler.NewDelegationHandler(delegationService, cfg)
+	companyHandler := handler.NewCompanyHandler(companyService, cfg)
+	auditHandler := handler.NewAuditHandler(auditService, cfg)
+	reimbursementHandler := handler.NewReimbursementHandler(reimbursementService, cfg)
+	reportHandler := handler.NewExpenseReportHandler(reportService, cfg)
 	ocrHandler := handler.NewOCRHandler(ocrService, ocrResultRepo, expenseService, cfg)
 
 	// API v1 group
-	api := app.Group("/api/v1")
+	api := app.Group("/api/v1", middleware.ClientIPMiddleware())
 
 	// Public routes (no authentication required)
 	auth := api.Group("/auth")
@@ -49,6 +78,13 @@ func SetupRoutes(app *fiber.App, cfg *config.Config) {
 		auth.Post("/refresh", authHandler.RefreshToken)
 	}
 
+	// Signed one-time approve/reject links, authorized by the token itself
+	actionLinks := api.Group("/action-links")
+	{
+		actionLinks.Get("/:token", approvalHandler.PreviewActionLink)
+		actionLinks.Post("/:token", approvalHandler.ConfirmActionLink)
+	}
+
 	// Protected routes (authentication required)
 	protected := api.Group("", middleware.AuthMiddleware(cfg))
 	{
@@ -61,13 +97,17 @@ func SetupRoutes(app *fiber.App, cfg *config.Config) {
 			// Admin only routes
 			users.Post("/", middleware.RoleMiddleware("admin"), userHandler.CreateUser)
 			users.Put("/:id/role", middleware.RoleMiddleware("admin"), userHandler.UpdateUserRole)
+			users.Put("/:id/approval-limit", middleware.RoleMiddleware("admin"), userHandler.UpdateApprovalLimit)
+			users.Put("/:id/department", middleware.RoleMiddleware("admin"), userHandler.UpdateDepartment)
 			users.Delete("/:id", middleware.RoleMiddleware("admin"), userHandler.DeleteUser)
 
 			// Admin and Manager routes
 			users.Get("/", middleware.RoleMiddleware("admin", "manager"), userHandler.GetUsers)
 			users.Put("/:id/manager", middleware.RoleMiddleware("admin", "manager"), userHandler.AssignManager)
 
-			// All authenticated users
+			// All authenticated users - own settings before /:id
+			users.Get("/me/digest", userHandler.GetDigestSettings)
+			users.Put("/me/digest", userHandler.UpdateDigestSettings)
 			users.Get("/:id", userHandler.GetUser)
 		}
 
@@ -85,20 +125,97 @@ func SetupRoutes(app *fiber.App, cfg *config.Config) {
 			expenses.Get("/:id", expenseHandler.GetExpense)
 			expenses.Put("/:id", expenseHandler.UpdateExpense)
 			expenses.Delete("/:id", expenseHandler.DeleteExpense)
+			expenses.Post("/:id/resubmit", expenseHandler.ResubmitExpense)
+			expenses.Post("/:id/withdraw", expenseHandler.WithdrawExpense)
+			expenses.Post("/:id/submit", expenseHandler.SubmitExpense)
+			expenses.Post("/:id/split", expenseHandler.SplitExpense)
+		}
+
+		// Expense report routes
+		reports := protected.Group("/reports")
+		{
+			// All authenticated users
+			reports.Post("/", reportHandler.CreateReport)
+			reports.Get("/", reportHandler.GetReports)
+			reports.Get("/:id", reportHandler.GetReport)
+			reports.Put("/:id", reportHandler.UpdateReport)
+			reports.Delete("/:id", reportHandler.DeleteReport)
+			reports.Post("/:id/expenses", reportHandler.AddExpenses)
+			reports.Delete("/:id/expenses/:expenseId", reportHandler.RemoveExpense)
+			reports.Post("/:id/submit", reportHandler.SubmitReport)
+
+			// Manager and Admin only
+			reports.Post("/:id/expenses/:expenseId/reject", middleware.RoleMiddleware("admin", "manager"), reportHandler.RejectLine)
 		}
 
 		// Approval routes
 		approvals := protected.Group("/approvals")
 		{
+			// Admin only
+			approvals.Post("/reroute", middleware.RoleMiddleware("admin"), approvalHandler.RerouteExpenses)
+			approvals.Post("/simulate", middleware.RoleMiddleware("admin"), approvalHandler.SimulateRoute)
+
 			// Manager and Admin only
 			approvals.Get("/pending", middleware.RoleMiddleware("admin", "manager"), approvalHandler.GetPendingApprovals)
+			approvals.Post("/bulk", middleware.RoleMiddleware("admin", "manager"), approvalHandler.BulkProcessApprovals)
 			approvals.Post("/:id/approve", middleware.RoleMiddleware("admin", "manager"), approvalHandler.ApproveExpense)
 			approvals.Post("/:id/reject", middleware.RoleMiddleware("admin", "manager"), approvalHandler.RejectExpense)
+			approvals.Post("/:id/request-changes", middleware.RoleMiddleware("admin", "manager"), approvalHandler.RequestChanges)
+			approvals.Post("/:id/action-links", middleware.RoleMiddleware("admin", "manager"), approvalHandler.CreateActionLinks)
 
 			// All authenticated users can view approval history
 			approvals.Get("/history/:expenseId", approvalHandler.GetApprovalHistory)
 		}
 
+		// Approval rule routes
+		approvalRules := protected.Group("/approval-rules")
+		{
+			// Admin only routes
+			approvalRules.Post("/", middleware.RoleMiddleware("admin"), approvalRuleHandler.CreateRule)
+			approvalRules.Put("/:id", middleware.RoleMiddleware("admin"), approvalRuleHandler.UpdateRule)
+			approvalRules.Put("/:id/activate", middleware.RoleMiddleware("admin"), approvalRuleHandler.ActivateRule)
+			approvalRules.Put("/:id/deactivate", middleware.RoleMiddleware("admin"), approvalRuleHandler.DeactivateRule)
+			approvalRules.Delete("/:id", middleware.RoleMiddleware("admin"), approvalRuleHandler.DeleteRule)
+
+			// Admin and Manager routes
+			approvalRules.Get("/", middleware.RoleMiddleware("admin", "manager"), approvalRuleHandler.GetRules)
+			approvalRules.Get("/:id", middleware.RoleMiddleware("admin", "manager"), approvalRuleHandler.GetRule)
+		}
+
+		// Company routes
+		company := protected.Group("/company")
+		{
+			company.Get("/settings", middleware.RoleMiddleware("admin", "manager"), companyHandler.GetSettings)
+			company.Put("/settings", middleware.RoleMiddleware("admin"), companyHandler.UpdateSettings)
+		}
+
+		// Audit trail routes
+		audit := protected.Group("/audit", middleware.RoleMiddleware("admin"))
+		{
+			audit.Get("/verify", auditHandler.VerifyChain)
+			audit.Get("/entities/:id", auditHandler.GetEntityTrail)
+		}
+
+		// Reimbursement routes
+		reimbursements := protected.Group("/reimbursements", middleware.RoleMiddleware("admin"))
+		{
+			reimbursements.Post("/batches", reimbursementHandler.CreateBatches)
+			reimbursements.Get("/batches", reimbursementHandler.GetBatches)
+			reimbursements.Get("/batches/:id", reimbursementHandler.GetBatch)
+			reimbursements.Post("/batches/:id/pay", reimbursementHandler.PayBatch)
+		}
+
+		// Delegation routes
+		delegations := protected.Group("/delegations", middleware.RoleMiddleware("admin", "manager"))
+		{
+			delegations.Post("/", delegationHandler.CreateDelegation)
+			delegations.Get("/", delegationHandler.GetDelegations)
+			delegations.Get("/:id", delegationHandler.GetDelegation)
+			delegations.Put("/:id", delegationHandler.UpdateDelegation)
+			delegations.Delete("/:id", delegationHandler.RevokeDelegation)
+			delegations.Post("/:id/reassign", delegationHandler.ReassignPending)
+		}
+
 		// OCR routes
 		ocr := protected.Group("/ocr")
 		{
@@ -114,4 +231,10 @@ func SetupRoutes(app *fiber.App, cfg *config.Config) {
 			"error":   "Route not found",
 		})
 	})
+
+	return &Services{
+		Approval: approvalService,
+		Digest:   digestService,
+		Expense:  expenseService,
+	}
 }
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/cache"
+	jwtUtil "expensio-backend/pkg/jwt"
+)
+
+// ActionLink is a signed link that performs one decision on an approval
+// without a session. It works once and until it expires.
+type ActionLink struct {
+	Action    BulkAction `json:"action"`
+	URL       string     `json:"url"`
+	ExpiresAt time.Time  `json:"expires_at"`
+}
+
+// ActionLinkPreview is what an approver confirms before a link is used
+type ActionLinkPreview struct {
+	Action        BulkAction      `json:"action"`
+	ApprovalID    string          `json:"approval_id"`
+	Expense       *domain.Expense `json:"expense"`
+	SubmitterName string          `json:"submitter_name"`
+	ExpiresAt     time.Time       `json:"expires_at"`
+}
+
+// actionLinkKey is the Redis key that keeps an unused link alive
+func actionLinkKey(tokenID string) string {
+	return fmt.Sprintf("actionlink:token:%s", tokenID)
+}
+
+// CreateActionLinks mints an approve and a reject link for the approver's
+// open approval. Approvers get a pair by mail whenever an approval is
+// assigned to them.
+func (s *ApprovalService) CreateActionLinks(ctx context.Context, approvalID, approverID string) ([]*ActionLink, error) {
+	approval, _, err := s.loadApprovalForAction(ctx, approvalID, approverID, "act on")
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]*ActionLink, 0, 2)
+	for _, action := range []BulkAction{BulkApprove, BulkReject} {
+		link, err := s.mintActionLink(approval, action)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// mintActionLink signs a token for the decision and registers it as unused
+func (s *ApprovalService) mintActionLink(approval *domain.Approval, action BulkAction) (*ActionLink, error) {
+	token, claims, err := jwtUtil.GenerateActionToken(approval.ID.Hex(), approval.ApproverID.Hex(), string(action), s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign action link: %w", err)
+	}
+
+	if err := cache.SetString(actionLinkKey(claims.ID), approval.ID.Hex(), s.cfg.ActionLink.Expiry); err != nil {
+		return nil, fmt.Errorf("failed to store action link: %w", err)
+	}
+
+	return &ActionLink{
+		Action:    action,
+		URL:       fmt.Sprintf("%s/%s", s.cfg.ActionLink.BaseURL, token),
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// PreviewActionLink returns what the link would do without using it up
+func (s *ApprovalService) PreviewActionLink(ctx context.Context, token string) (*ActionLinkPreview, error) {
+	claims, err := s.parseActionLink(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureLinkApprover(ctx, claims.ApproverID); err != nil {
+		return nil, err
+	}
+
+	unused, err := cache.Exists(actionLinkKey(claims.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check action link: %w", err)
+	}
+	if !unused {
+		return nil, fmt.Errorf("this link was already used or has expired")
+	}
+
+	_, expense, err := s.loadApprovalForAction(ctx, claims.ApprovalID, claims.ApproverID, claims.Action)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &ActionLinkPreview{
+		Action:     BulkAction(claims.Action),
+		ApprovalID: claims.ApprovalID,
+		Expense:    expense,
+		ExpiresAt:  claims.ExpiresAt.Time,
+	}
+	if submitter, err := s.userRepo.FindByID(ctx, expense.UserID.Hex()); err == nil {
+		preview.SubmitterName = submitter.FirstName + " " + submitter.LastName
+	}
+
+	return preview, nil
+}
+
+// ConfirmActionLink uses up the link and performs its decision as the
+// approver it was minted for. A link is spent even when the decision is
+// refused, the approver can always act from the dashboard instead.
+func (s *ApprovalService) ConfirmActionLink(ctx context.Context, token string, req *ApprovalActionRequest) (BulkAction, error) {
+	claims, err := s.parseActionLink(token)
+	if err != nil {
+		return "", err
+	}
+
+	// The link may be older than a deactivation or a role change
+	if err := s.ensureLinkApprover(ctx, claims.ApproverID); err != nil {
+		return "", err
+	}
+
+	taken, err := cache.Take(actionLinkKey(claims.ID))
+	if err != nil {
+		return "", fmt.Errorf("failed to use action link: %w", err)
+	}
+	if !taken {
+		return "", fmt.Errorf("this link was already used or has expired")
+	}
+
+	action := BulkAction(claims.Action)
+	fmt.Printf("🔗 Action link used to %s approval %s\n", action, claims.ApprovalID)
+
+	// There is no session, the link speaks for its approver
+	ctx = withAuditActor(ctx, claims.ApproverID)
+
+	if action == BulkApprove {
+		return action, s.ApproveExpenseByApprovalID(ctx, claims.ApprovalID, claims.ApproverID, req)
+	}
+	return action, s.RejectExpenseByApprovalID(ctx, claims.ApprovalID, claims.ApproverID, req)
+}
+
+// ensureLinkApprover refuses links of approvers who are no longer active or
+// no longer a manager or admin, like the dashboard routes do
+func (s *ApprovalService) ensureLinkApprover(ctx context.Context, approverID string) error {
+	approver, err := s.userRepo.FindByID(ctx, approverID)
+	if err != nil || !approver.IsActive {
+		return &actionError{outcome: BulkForbidden, message: "your account is no longer active"}
+	}
+	if approver.Role != domain.RoleManager && approver.Role != domain.RoleAdmin {
+		return &actionError{outcome: BulkForbidden, message: "you are no longer allowed to approve expenses"}
+	}
+	return nil
+}
+
+// parseActionLink verifies the link's signature, expiry and action
+func (s *ApprovalService) parseActionLink(token string) (*jwtUtil.ActionClaims, error) {
+	claims, err := jwtUtil.ValidateActionToken(token, s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("this link is invalid or has expired")
+	}
+	if action := BulkAction(claims.Action); action != BulkApprove && action != BulkReject {
+		return nil, fmt.Errorf("this link is invalid or has expired")
+	}
+	return claims, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// maxBulkApprovals bounds the number of approvals a single bulk action may touch
+const maxBulkApprovals = 100
+
+type BulkAction string
+
+const (
+	BulkApprove BulkAction = "approve"
+	BulkReject  BulkAction = "reject"
+)
+
+type BulkOutcome string
+
+const (
+	BulkSucceeded BulkOutcome = "succeeded"
+	BulkSkipped   BulkOutcome = "skipped"   // Approval or expense was already decided
+	BulkForbidden BulkOutcome = "forbidden" // Approval is assigned to someone else
+	BulkFailed    BulkOutcome = "failed"
+)
+
+type BulkApprovalRequest struct {
+	ApprovalIDs []string   `json:"approval_ids"`
+	Action      BulkAction `json:"action"`
+	Comments    string     `json:"comments,omitempty"` // Shared by every item
+}
+
+type BulkApprovalResult struct {
+	Succeeded int                      `json:"succeeded"`
+	Skipped   int                      `json:"skipped"`
+	Forbidden int                      `json:"forbidden"`
+	Failed    int                      `json:"failed"`
+	Items     []BulkApprovalItemResult `json:"items"`
+}
+
+type BulkApprovalItemResult struct {
+	ApprovalID string      `json:"approval_id"`
+	Outcome    BulkOutcome `json:"outcome"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// actionError is an approval action refused for a reason bulk callers report
+// separately from plain failures
+type actionError struct {
+	outcome BulkOutcome
+	message string
+}
+
+func (e *actionError) Error() string {
+	return e.message
+}
+
+// BulkProcessApprovals approves or rejects each approval independently with the
+// same semantics as the single-item actions and reports the outcome per item.
+// Caches are invalidated once per approver after the whole batch.
+func (s *ApprovalService) BulkProcessApprovals(ctx context.Context, approverID string, req *BulkApprovalRequest) (*BulkApprovalResult, error) {
+	if req.Action != BulkApprove && req.Action != BulkReject {
+		return nil, fmt.Errorf("action must be %q or %q", BulkApprove, BulkReject)
+	}
+	if len(req.ApprovalIDs) == 0 {
+		return nil, fmt.Errorf("approval_ids cannot be empty")
+	}
+	if len(req.ApprovalIDs) > maxBulkApprovals {
+		return nil, fmt.Errorf("at most %d approvals can be processed at once", maxBulkApprovals)
+	}
+
+	actionReq := &ApprovalActionRequest{Comments: req.Comments}
+	caches := approvalCaches{}
+	defer s.flushApprovalCaches(caches)
+
+	result := &BulkApprovalResult{Items: make([]BulkApprovalItemResult, 0, len(req.ApprovalIDs))}
+	seen := make(map[string]bool, len(req.ApprovalIDs))
+	for _, approvalID := range req.ApprovalIDs {
+		item := BulkApprovalItemResult{ApprovalID: approvalID, Outcome: BulkSucceeded}
+
+		if seen[approvalID] {
+			item.Outcome = BulkSkipped
+			item.Error = "approval is listed more than once"
+		} else if err := s.bulkProcessApproval(ctx, approvalID, approverID, req.Action, actionReq, caches); err != nil {
+			item.Outcome = BulkFailed
+			var refused *actionError
+			if errors.As(err, &refused) {
+				item.Outcome = refused.outcome
+			}
+			item.Error = err.Error()
+		}
+		seen[approvalID] = true
+
+		switch item.Outcome {
+		case BulkSucceeded:
+			result.Succeeded++
+		case BulkSkipped:
+			result.Skipped++
+		case BulkForbidden:
+			result.Forbidden++
+		default:
+			result.Failed++
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	fmt.Printf("📦 Bulk %s by %s: %d succeeded, %d skipped, %d forbidden, %d failed\n",
+		req.Action, approverID, result.Succeeded, result.Skipped, result.Forbidden, result.Failed)
+
+	return result, nil
+}
+
+// bulkProcessApproval applies the action to a single approval of the batch
+func (s *ApprovalService) bulkProcessApproval(ctx context.Context, approvalID, approverID string, action BulkAction, req *ApprovalActionRequest, caches approvalCaches) error {
+	approval, expense, err := s.loadApprovalForAction(ctx, approvalID, approverID, string(action))
+	if err != nil {
+		return err
+	}
+
+	if action == BulkReject {
+		return s.reject(ctx, expense, approval, req, caches)
+	}
+	return s.approve(ctx, expense, approval, req, caches)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"expensio-backend/internal/domain"
+)
+
+// maxTransitionAttempts bounds how often an approval re-evaluates its expense
+// after losing against concurrent writes
+const maxTransitionAttempts = 5
+
+// claimApproval writes the decision on approval if it is still in the from
+// status. Losing against a concurrent decision is reported as skipped.
+func (s *ApprovalService) claimApproval(ctx context.Context, approval *domain.Approval, from domain.ApprovalStatus) error {
+	err := s.approvalRepo.Transition(ctx, approval, from)
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return &actionError{outcome: BulkSkipped, message: "approval was already decided by a concurrent request"}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update approval: %w", err)
+	}
+	return nil
+}
+
+// recordApproval appends the decision on approval to the audit trail
+func (s *ApprovalService) recordApproval(ctx context.Context, expense *domain.Expense, approval *domain.Approval, action domain.AuditAction, before json.RawMessage) {
+	s.auditService.Record(ctx, expense.CompanyID, action, auditEntityApproval, approval.ID, before, approval)
+}
+
+// transitionExpense moves the expense between statuses like
+// ExpenseRepository.TransitionStatus and records the change
+func (s *ApprovalService) transitionExpense(ctx context.Context, expense *domain.Expense, from, to domain.ExpenseStatus, action domain.AuditAction) error {
+	before := auditSnapshot(expense)
+	if err := s.expenseRepo.TransitionStatus(ctx, expense, from, to); err != nil {
+		return err
+	}
+	s.auditService.Record(ctx, expense.CompanyID, action, auditEntityExpense, expense.ID, before, expense)
+	s.settleReport(ctx, expense)
+	return nil
+}
+
+// settleReport passes the decision on an expense that carries an expense
+// report on to the report and its expenses
+func (s *ApprovalService) settleReport(ctx context.Context, expense *domain.Expense) {
+	if expense.RoutedReportID == nil || s.reportService == nil {
+		return
+	}
+	s.reportService.settleReport(ctx, expense)
+}
+
+// advanceExpense re-evaluates the expense after approval was claimed and
+// either approves it or moves it to the next level. Each attempt reads the
+// approvals after its own was written, so of two approvers acting at once at
+// least the later one sees both decisions, and writes that lose against a
+// concurrent one are retried on the fresh expense.
+func (s *ApprovalService) advanceExpense(ctx context.Context, expense *domain.Expense, approval *domain.Approval, caches approvalCaches) error {
+	for attempt := 0; attempt < maxTransitionAttempts; attempt++ {
+		if attempt > 0 {
+			reloaded, err := s.expenseRepo.FindByID(ctx, expense.ID.Hex())
+			if err != nil {
+				return expenseDeletedError()
+			}
+			*expense = *reloaded
+		}
+
+		if expense.ApprovalRound != approval.Round {
+			return &actionError{outcome: BulkSkipped, message: "expense was re-routed by a concurrent request"}
+		}
+		if expense.Status != domain.StatusPending {
+			return s.concurrentDecisionError(ctx, expense, domain.StatusApproved)
+		}
+
+		approvals, err := s.approvalRepo.FindByExpenseID(ctx, expense.ID.Hex())
+		if err != nil {
+			return fmt.Errorf("failed to fetch approvals: %w", err)
+		}
+		approvals = currentApprovals(expense, approvals)
+
+		// Check if expense should be auto-approved based on rules
+		shouldAutoApprove, err := s.checkAutoApproval(ctx, expense, approvals)
+		if err != nil {
+			return fmt.Errorf("failed to check auto-approval: %w", err)
+		}
+
+		if shouldAutoApprove {
+			err = s.transitionExpense(ctx, expense, domain.StatusPending, domain.StatusApproved, domain.AuditExpenseDecided)
+		} else {
+			// Hand a sequential chain over to the next level
+			var promoted []*domain.Approval
+			promoted, err = s.promoteNextLevel(ctx, approvals, approval.Level)
+			if err != nil {
+				return err
+			}
+			for _, next := range promoted {
+				caches.add(expense.CompanyID.Hex(), next.ApproverID.Hex())
+				s.notifyAssigned(ctx, expense, next)
+			}
+
+			// The expense moves on once per level, whichever approval completed
+			// it and however often that approval retried
+			if !nextLevelReached(approvals, approval.Level) || expense.CurrentApprovalLevel >= approval.Level {
+				return nil
+			}
+			expense.CurrentApprovalLevel = approval.Level
+			err = s.expenseRepo.Update(ctx, expense)
+		}
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			continue
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			return expenseDeletedError()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update expense: %w", err)
+		}
+
+		return nil
+	}
+
+	fmt.Printf("⚠️  Expense %s kept changing while approval %s was applied\n", expense.ID.Hex(), approval.ID.Hex())
+
+	return fmt.Errorf("your approval was recorded, but the expense is being updated by other requests; reload it to see its state")
+}
+
+// nextLevelReached reports whether every approval on level is approved and
+// the chain continues on the level after it
+func nextLevelReached(approvals []*domain.Approval, level int) bool {
+	next := false
+	for _, approval := range approvals {
+		if approval.Level == level && approval.Status != domain.ApprovalApproved {
+			return false
+		}
+		if approval.Level == level+1 {
+			next = true
+		}
+	}
+	return next
+}
+
+// concurrentDecisionError reports an expense that a concurrent request moved
+// out of pending. Reaching the outcome the caller wanted is not an error.
+func (s *ApprovalService) concurrentDecisionError(ctx context.Context, expense *domain.Expense, wanted domain.ExpenseStatus) error {
+	if reloaded, err := s.expenseRepo.FindByID(ctx, expense.ID.Hex()); err == nil {
+		*expense = *reloaded
+	}
+	if expense.Status == wanted {
+		return nil
+	}
+	return &actionError{outcome: BulkSkipped, message: fmt.Sprintf("your decision was recorded, but a concurrent request already moved the expense to %s", expense.Status)}
+}
+
+// expenseDeletedError reports an expense that was deleted while a decision
+// on it was applied
+func expenseDeletedError() error {
+	return &actionError{outcome: BulkSkipped, message: "your decision was recorded, but the expense was deleted"}
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/notifier"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memExpenseRepo keeps expenses in memory with the conditional write
+// semantics of the Mongo repository
+type memExpenseRepo struct {
+	domain.ExpenseRepository
+	mu       sync.Mutex
+	expenses map[primitive.ObjectID]domain.Expense
+}
+
+func (r *memExpenseRepo) FindByID(ctx context.Context, id string) (*domain.Expense, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	objectID, _ := primitive.ObjectIDFromHex(id)
+	expense, ok := r.expenses[objectID]
+	if !ok {
+		return nil, errors.New("expense not found")
+	}
+	return &expense, nil
+}
+
+func (r *memExpenseRepo) Update(ctx context.Context, expense *domain.Expense) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.expenses[expense.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if stored.Version != expense.Version {
+		return domain.ErrConcurrentUpdate
+	}
+	expense.Version++
+	r.expenses[expense.ID] = *expense
+	return nil
+}
+
+func (r *memExpenseRepo) TransitionStatus(ctx context.Context, expense *domain.Expense, from, to domain.ExpenseStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.expenses[expense.ID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if stored.Status != from {
+		return domain.ErrConcurrentUpdate
+	}
+	stored.Status = to
+	stored.Version++
+	r.expenses[expense.ID] = stored
+	*expense = stored
+	return nil
+}
+
+// memApprovalRepo keeps approvals in memory
+type memApprovalRepo struct {
+	domain.ApprovalRepository
+	mu        sync.Mutex
+	approvals map[primitive.ObjectID]domain.Approval
+}
+
+func (r *memApprovalRepo) FindByExpenseID(ctx context.Context, expenseID string) ([]*domain.Approval, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var approvals []*domain.Approval
+	for _, approval := range r.approvals {
+		if approval.ExpenseID.Hex() == expenseID {
+			approval := approval
+			approvals = append(approvals, &approval)
+		}
+	}
+	return approvals, nil
+}
+
+func (r *memApprovalRepo) Transition(ctx context.Context, approval *domain.Approval, from domain.ApprovalStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.approvals[approval.ID].Status != from {
+		return domain.ErrConcurrentUpdate
+	}
+	r.approvals[approval.ID] = *approval
+	return nil
+}
+
+// memUserRepo finds no users, so no approval limit applies
+type memUserRepo struct {
+	domain.UserRepository
+}
+
+func (r *memUserRepo) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	return nil, errors.New("user not found")
+}
+
+// memAuditRepo keeps the audit chain in memory
+type memAuditRepo struct {
+	domain.AuditRepository
+	mu      sync.Mutex
+	entries []*domain.AuditEntry
+}
+
+func (r *memAuditRepo) FindLastByCompanyID(ctx context.Context, companyID string) (*domain.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return nil, nil
+	}
+	return r.entries[len(r.entries)-1], nil
+}
+
+func (r *memAuditRepo) Append(ctx context.Context, entry *domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if int64(len(r.entries))+1 != entry.Sequence {
+		return domain.ErrConcurrentUpdate
+	}
+	stored := *entry
+	r.entries = append(r.entries, &stored)
+	return nil
+}
+
+func (r *memAuditRepo) count(action domain.AuditAction) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, entry := range r.entries {
+		if entry.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+type concurrencyFixture struct {
+	service   *ApprovalService
+	expenses  *memExpenseRepo
+	approvals *memApprovalRepo
+	audit     *memAuditRepo
+	expense   domain.Expense
+}
+
+// newConcurrencyFixture creates a pending expense with one pending approval
+// per approver, all on the first level
+func newConcurrencyFixture(approvers int) *concurrencyFixture {
+	expense := domain.Expense{
+		ID:        primitive.NewObjectID(),
+		CompanyID: primitive.NewObjectID(),
+		UserID:    primitive.NewObjectID(),
+		Status:    domain.StatusPending,
+	}
+
+	f := &concurrencyFixture{
+		expenses:  &memExpenseRepo{expenses: map[primitive.ObjectID]domain.Expense{expense.ID: expense}},
+		approvals: &memApprovalRepo{approvals: make(map[primitive.ObjectID]domain.Approval)},
+		audit:     &memAuditRepo{},
+		expense:   expense,
+	}
+	for i := 0; i < approvers; i++ {
+		approval := domain.Approval{
+			ID:         primitive.NewObjectID(),
+			ExpenseID:  expense.ID,
+			ApproverID: primitive.NewObjectID(),
+			Level:      1,
+			Status:     domain.ApprovalPending,
+		}
+		f.approvals.approvals[approval.ID] = approval
+	}
+
+	f.service = NewApprovalService(f.approvals, nil, f.expenses, &memUserRepo{}, nil, nil, NewAuditService(f.audit, nil), notifier.NewLogNotifier(), nil)
+	return f
+}
+
+// act runs each decision on its own copy of the expense and approval, all at once
+func (f *concurrencyFixture) act(decisions map[primitive.ObjectID][]BulkAction) []error {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		errs  []error
+		start = make(chan struct{})
+	)
+	for approvalID, actions := range decisions {
+		for _, action := range actions {
+			wg.Add(1)
+			go func(approval domain.Approval, action BulkAction) {
+				defer wg.Done()
+				expense := f.expense
+				<-start
+
+				var err error
+				if action == BulkReject {
+					err = f.service.reject(context.Background(), &expense, &approval, &ApprovalActionRequest{}, approvalCaches{})
+				} else {
+					err = f.service.approve(context.Background(), &expense, &approval, &ApprovalActionRequest{}, approvalCaches{})
+				}
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}(f.approvals.approvals[approvalID], action)
+		}
+	}
+	close(start)
+	wg.Wait()
+	return errs
+}
+
+// route sets the rule the expense was routed under
+func (f *concurrencyFixture) route(rule *domain.ApprovalRule) {
+	f.expense.ApprovalRule = rule
+	f.expenses.expenses[f.expense.ID] = f.expense
+}
+
+// queueLevel adds a queued approval on level
+func (f *concurrencyFixture) queueLevel(level int) primitive.ObjectID {
+	approval := domain.Approval{
+		ID:         primitive.NewObjectID(),
+		ExpenseID:  f.expense.ID,
+		ApproverID: primitive.NewObjectID(),
+		Level:      level,
+		Status:     domain.ApprovalQueued,
+	}
+	f.approvals.approvals[approval.ID] = approval
+	return approval.ID
+}
+
+func (f *concurrencyFixture) stored(t *testing.T) *domain.Expense {
+	t.Helper()
+	expense, err := f.expenses.FindByID(context.Background(), f.expense.ID.Hex())
+	if err != nil {
+		t.Fatalf("expense is gone: %v", err)
+	}
+	return expense
+}
+
+func (f *concurrencyFixture) approvalIDs() []primitive.ObjectID {
+	var ids []primitive.ObjectID
+	for id := range f.approvals.approvals {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestConcurrentApproveAndRejectOfOneApproval(t *testing.T) {
+	for run := 0; run < 50; run++ {
+		f := newConcurrencyFixture(1)
+		approvalID := f.approvalIDs()[0]
+
+		f.act(map[primitive.ObjectID][]BulkAction{approvalID: {BulkApprove, BulkReject}})
+
+		approvals := f.audit.count(domain.AuditApprovalApproved)
+		rejections := f.audit.count(domain.AuditApprovalRejected)
+		if approvals+rejections != 1 {
+			t.Fatalf("run %d: want exactly one recorded decision, got %d approvals and %d rejections", run, approvals, rejections)
+		}
+		if decided := f.audit.count(domain.AuditExpenseDecided); decided != 1 {
+			t.Fatalf("run %d: want one expense decision in the audit trail, got %d", run, decided)
+		}
+
+		want := domain.StatusApproved
+		if rejections == 1 {
+			want = domain.StatusRejected
+		}
+		expense := f.stored(t)
+		if expense.Status != want {
+			t.Fatalf("run %d: want expense %s after the winning decision, got %s", run, want, expense.Status)
+		}
+		if expense.CurrentApprovalLevel != 0 {
+			t.Fatalf("run %d: a final decision must not advance the level, got %d", run, expense.CurrentApprovalLevel)
+		}
+	}
+}
+
+func TestConcurrentApprovalsOfOneLevel(t *testing.T) {
+	for run := 0; run < 50; run++ {
+		f := newConcurrencyFixture(2)
+		decisions := make(map[primitive.ObjectID][]BulkAction)
+		for _, id := range f.approvalIDs() {
+			decisions[id] = []BulkAction{BulkApprove}
+		}
+
+		for _, err := range f.act(decisions) {
+			if err != nil {
+				t.Fatalf("run %d: approval failed: %v", run, err)
+			}
+		}
+
+		if approvals := f.audit.count(domain.AuditApprovalApproved); approvals != 2 {
+			t.Fatalf("run %d: want both approvals recorded once, got %d", run, approvals)
+		}
+		if decided := f.audit.count(domain.AuditExpenseDecided); decided != 1 {
+			t.Fatalf("run %d: want one expense decision in the audit trail, got %d", run, decided)
+		}
+
+		expense := f.stored(t)
+		if expense.Status != domain.StatusApproved {
+			t.Fatalf("run %d: want expense approved, got %s", run, expense.Status)
+		}
+		if expense.CurrentApprovalLevel > 1 {
+			t.Fatalf("run %d: level advanced %d times for one level", run, expense.CurrentApprovalLevel)
+		}
+	}
+}
+
+func TestApprovalOfDeletedExpense(t *testing.T) {
+	f := newConcurrencyFixture(1)
+	approval := f.approvals.approvals[f.approvalIDs()[0]]
+	delete(f.expenses.expenses, f.expense.ID)
+
+	expense := f.expense
+	err := f.service.approve(context.Background(), &expense, &approval, &ApprovalActionRequest{}, approvalCaches{})
+
+	var refused *actionError
+	if !errors.As(err, &refused) || refused.outcome != BulkSkipped {
+		t.Fatalf("want the approval skipped because the expense is gone, got %v", err)
+	}
+	if err.Error() != expenseDeletedError().Error() {
+		t.Fatalf("want the deletion reported, got %q", err)
+	}
+}
+
+func TestPartialApprovalKeepsLevel(t *testing.T) {
+	f := newConcurrencyFixture(3)
+	twoOfThree := 66.0
+	f.route(&domain.ApprovalRule{Type: domain.RuleTypePercentage, PercentageRequired: &twoOfThree})
+
+	first := f.approvalIDs()[0]
+	for _, err := range f.act(map[primitive.ObjectID][]BulkAction{first: {BulkApprove}}) {
+		if err != nil {
+			t.Fatalf("approval failed: %v", err)
+		}
+	}
+
+	expense := f.stored(t)
+	if expense.Status != domain.StatusPending {
+		t.Fatalf("want expense pending after one of three approvals, got %s", expense.Status)
+	}
+	if expense.CurrentApprovalLevel != 0 {
+		t.Fatalf("an approval that does not complete the level must not advance it, got level %d", expense.CurrentApprovalLevel)
+	}
+}
+
+func TestConcurrentApprovalsAdvanceLevelOnce(t *testing.T) {
+	for run := 0; run < 50; run++ {
+		f := newConcurrencyFixture(2)
+		f.route(&domain.ApprovalRule{Type: domain.RuleTypeSequential})
+		decisions := make(map[primitive.ObjectID][]BulkAction)
+		for _, id := range f.approvalIDs() {
+			decisions[id] = []BulkAction{BulkApprove}
+		}
+		next := f.queueLevel(2)
+
+		for _, err := range f.act(decisions) {
+			if err != nil {
+				t.Fatalf("run %d: approval failed: %v", run, err)
+			}
+		}
+
+		expense := f.stored(t)
+		if expense.Status != domain.StatusPending {
+			t.Fatalf("run %d: want expense pending on the second level, got %s", run, expense.Status)
+		}
+		if expense.CurrentApprovalLevel != 1 {
+			t.Fatalf("run %d: want the first level completed once, got level %d", run, expense.CurrentApprovalLevel)
+		}
+		if status := f.approvals.approvals[next].Status; status != domain.ApprovalPending {
+			t.Fatalf("run %d: want the second level promoted, got %s", run, status)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"expensio-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// selfApprovalSubstitute returns who approves in place of the submitter under
+// the substitute policy: their active manager, or else the company admin.
+// Nil means the submitter is skipped, also when the substitute is already
+// asked to approve the expense.
+func (s *ApprovalService) selfApprovalSubstitute(ctx context.Context, expense *domain.Expense, plan *ApprovalPlan, approverIDs []primitive.ObjectID) *primitive.ObjectID {
+	if plan.settings.SelfApprovalPolicy != domain.SelfApprovalSubstitute {
+		return nil
+	}
+
+	var candidates []primitive.ObjectID
+	if managerID := s.submitterManagerID(ctx, expense); managerID != nil {
+		manager, err := s.userRepo.FindByID(ctx, managerID.Hex())
+		if err == nil && manager.IsActive && manager.CompanyID == expense.CompanyID {
+			candidates = append(candidates, *managerID)
+		}
+	}
+	if plan.adminID != nil {
+		candidates = append(candidates, *plan.adminID)
+	}
+
+	for _, candidateID := range candidates {
+		if candidateID == expense.UserID || containsObjectID(approverIDs, candidateID) || plannedFor(plan, candidateID) {
+			continue
+		}
+		substituteID := candidateID
+		return &substituteID
+	}
+	return nil
+}
+
+// plannedFor reports whether the plan already asks approverID
+func plannedFor(plan *ApprovalPlan, approverID primitive.ObjectID) bool {
+	for _, planned := range plan.Approvals {
+		if planned.ApproverID == approverID {
+			return true
+		}
+	}
+	return false
+}
+
+// planWithoutApprovers applies the company's no-approver policy when nobody
+// but the submitter could approve the expense, e.g. an admin without manager
+func (s *ApprovalService) planWithoutApprovers(ctx context.Context, expense *domain.Expense, plan *ApprovalPlan) {
+	switch plan.settings.NoApproverPolicy {
+	case domain.NoApproverFallback:
+		fallbackID := plan.settings.FallbackApproverID
+		if fallbackID != nil && *fallbackID != expense.UserID {
+			fmt.Printf("🛟 No independent approver for expense %s, asking fallback approver %s\n", expense.ID.Hex(), fallbackID.Hex())
+			plan.Fallback = true
+			plan.Approvals = append(plan.Approvals, s.planApproval(ctx, expense, *fallbackID, 1, domain.ApprovalPending))
+			plan.Decision = fmt.Sprintf("no independent approver, fallback approver %s approves", fallbackID.Hex())
+			return
+		}
+		fmt.Printf("⛔ No independent approver for expense %s and no usable fallback approver, rejecting\n", expense.ID.Hex())
+		plan.AutoReject = true
+		plan.Decision = "no independent approver and no usable fallback approver, the expense is rejected automatically"
+
+	case domain.NoApproverReject:
+		fmt.Printf("⛔ No independent approver for expense %s, rejecting\n", expense.ID.Hex())
+		plan.AutoReject = true
+		plan.Decision = "no independent approver, the expense is rejected automatically"
+
+	default:
+		fmt.Printf("⚠️  No independent approver for expense %s, auto-approving\n", expense.ID.Hex())
+		plan.AutoApprove = true
+		plan.Decision = "no independent approver, the expense is approved automatically"
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/businesshours"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EscalateStaleApprovals hands pending approvals that exceeded their company's
+// SLA over to the approver's manager, or to the company admin, and returns how
+// many were escalated. The SLA is counted in business hours.
+func (s *ApprovalService) EscalateStaleApprovals(ctx context.Context, now time.Time) (int, error) {
+	calendar := businesshours.NewCalendar(s.cfg.Escalation.WorkdayStart, s.cfg.Escalation.WorkdayEnd, s.cfg.Escalation.Timezone)
+
+	// Business time never exceeds wall time, so anything assigned within the
+	// last hour cannot have breached even the shortest SLA
+	approvals, err := s.approvalRepo.FindPendingAssignedBefore(ctx, now.Add(-time.Hour))
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch stale approvals: %w", err)
+	}
+
+	companies := make(map[primitive.ObjectID]*domain.Company)
+	escalated := 0
+	for _, approval := range approvals {
+		expense, err := s.expenseRepo.FindByID(ctx, approval.ExpenseID.Hex())
+		if err != nil || expense.Status != domain.StatusPending {
+			continue
+		}
+
+		company, ok := companies[expense.CompanyID]
+		if !ok {
+			company, err = s.companyRepo.FindByID(ctx, expense.CompanyID.Hex())
+			if err != nil {
+				fmt.Printf("⚠️  Failed to load company %s for escalation: %v\n", expense.CompanyID.Hex(), err)
+				continue
+			}
+			companies[expense.CompanyID] = company
+		}
+
+		sla := time.Duration(s.approvalSLAHours(company)) * time.Hour
+		if sla <= 0 || calendar.Elapsed(assignedAt(approval), now) < sla {
+			continue
+		}
+
+		if err := s.escalateApproval(ctx, expense, company, approval, now); err != nil {
+			fmt.Printf("⚠️  Could not escalate approval %s: %v\n", approval.ID.Hex(), err)
+			continue
+		}
+		escalated++
+	}
+
+	return escalated, nil
+}
+
+// approvalSLAHours returns the company's SLA, falling back to the server default
+func (s *ApprovalService) approvalSLAHours(company *domain.Company) int {
+	if company.Settings.ApprovalSLAHours > 0 {
+		return company.Settings.ApprovalSLAHours
+	}
+	return s.cfg.Escalation.DefaultSLAHours
+}
+
+// assignedAt returns when the approval started waiting on its approver
+func assignedAt(approval *domain.Approval) time.Time {
+	if approval.AssignedAt != nil {
+		return *approval.AssignedAt
+	}
+	return approval.CreatedAt
+}
+
+// escalateApproval hands the approval over to the escalation target
+func (s *ApprovalService) escalateApproval(ctx context.Context, expense *domain.Expense, company *domain.Company, approval *domain.Approval, now time.Time) error {
+	targetID, err := s.escalationTarget(ctx, expense, company, approval)
+	if err != nil {
+		return err
+	}
+
+	// The target may already be asked to approve this expense
+	approvals, err := s.approvalRepo.FindByExpenseID(ctx, expense.ID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to fetch approvals: %w", err)
+	}
+	if hasOpenApproval(approvals, targetID) {
+		return fmt.Errorf("escalation target %s already has an open approval", targetID.Hex())
+	}
+
+	reason := fmt.Sprintf("no decision within %d business hours", s.approvalSLAHours(company))
+	if _, err := s.handOverApproval(ctx, expense, approval, targetID, reason, now); err != nil {
+		return err
+	}
+
+	fmt.Printf("⏫ Escalated approval %s of expense %s from %s to %s\n",
+		approval.ID.Hex(), expense.ID.Hex(), approval.ApproverID.Hex(), targetID.Hex())
+
+	companyID := company.ID.Hex()
+	s.invalidateApprovalCaches(companyID, approval.ApproverID.Hex())
+	s.invalidateApprovalCaches(companyID, targetID.Hex())
+
+	return nil
+}
+
+// handOverApproval marks the approval as escalated for reason and creates a
+// pending approval at the same level for targetID, filling the same seat. The
+// approval must still be pending, so a decision taken meanwhile is kept.
+func (s *ApprovalService) handOverApproval(ctx context.Context, expense *domain.Expense, approval *domain.Approval, targetID primitive.ObjectID, reason string, now time.Time) (*domain.Approval, error) {
+	before := auditSnapshot(approval)
+	approval.Status = domain.ApprovalEscalated
+	approval.EscalatedAt = &now
+	approval.EscalatedToID = &targetID
+	approval.EscalationReason = reason
+	if err := s.claimApproval(ctx, approval, domain.ApprovalPending); err != nil {
+		return nil, err
+	}
+	s.recordApproval(ctx, expense, approval, domain.AuditApprovalEscalated, before)
+
+	originalID := approval.ID
+	onBehalfOfID := effectiveApproverID(approval)
+	replacement := &domain.Approval{
+		ExpenseID:       expense.ID,
+		ApproverID:      targetID,
+		OnBehalfOfID:    &onBehalfOfID,
+		Level:           approval.Level,
+		Round:           approval.Round,
+		Status:          domain.ApprovalPending,
+		AssignedAt:      &now,
+		EscalatedFromID: &originalID,
+	}
+	if err := s.approvalRepo.Create(ctx, replacement); err != nil {
+		return nil, fmt.Errorf("failed to create escalated approval: %w", err)
+	}
+	s.notifyAssigned(ctx, expense, replacement)
+
+	return replacement, nil
+}
+
+// hasOpenApproval reports whether approverID still has to act on one of the approvals
+func hasOpenApproval(approvals []*domain.Approval, approverID primitive.ObjectID) bool {
+	for _, approval := range approvals {
+		if approval.ApproverID == approverID &&
+			(approval.Status == domain.ApprovalPending || approval.Status == domain.ApprovalQueued) {
+			return true
+		}
+	}
+	return false
+}
+
+// escalationTarget returns the approver's active manager, or the company admin
+// when there is none. Nobody escalates to themselves or to the submitter.
+func (s *ApprovalService) escalationTarget(ctx context.Context, expense *domain.Expense, company *domain.Company, approval *domain.Approval) (primitive.ObjectID, error) {
+	approver, err := s.userRepo.FindByID(ctx, approval.ApproverID.Hex())
+	if err == nil && approver.ManagerID != nil {
+		managerID := *approver.ManagerID
+		if managerID != approval.ApproverID && managerID != expense.UserID {
+			manager, err := s.userRepo.FindByID(ctx, managerID.Hex())
+			if err == nil && manager.IsActive && manager.CompanyID == company.ID {
+				return managerID, nil
+			}
+		}
+	}
+
+	if company.AdminUserID != approval.ApproverID && company.AdminUserID != expense.UserID {
+		return company.AdminUserID, nil
+	}
+
+	return primitive.NilObjectID, fmt.Errorf("no escalation target above approver %s", approval.ApproverID.Hex())
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"expensio-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxExpressionDepth bounds the nesting of approval expressions
+const maxExpressionDepth = 8
+
+type ApprovalExpressionRequest struct {
+	Operator   domain.ExpressionOperator   `json:"operator"`
+	Conditions []ApprovalExpressionRequest `json:"conditions,omitempty"`
+	ApproverID string                      `json:"approver_id,omitempty"`
+	Approvers  []string                    `json:"approvers,omitempty"`
+	Count      int                         `json:"count,omitempty"`
+	Percentage float64                     `json:"percentage,omitempty"`
+	Amount     float64                     `json:"amount,omitempty"`
+}
+
+// parseExpression converts and validates an expression request. Errors name
+// the offending node by its path, e.g. expression.conditions[1].count.
+func parseExpression(req *ApprovalExpressionRequest, path string, depth int) (*domain.ApprovalExpression, error) {
+	if depth > maxExpressionDepth {
+		return nil, fmt.Errorf("%s: expressions cannot be nested deeper than %d levels", path, maxExpressionDepth)
+	}
+
+	expr := &domain.ApprovalExpression{Operator: req.Operator}
+	isGroup := req.Operator == domain.ExprAnd || req.Operator == domain.ExprOr
+
+	if !isGroup && len(req.Conditions) > 0 {
+		return nil, fmt.Errorf("%s.conditions: only \"and\" and \"or\" expressions take conditions", path)
+	}
+	if req.ApproverID != "" && req.Operator != domain.ExprSpecificApprover {
+		return nil, fmt.Errorf("%s.approver_id: not applicable to %q expressions", path, req.Operator)
+	}
+	if len(req.Approvers) > 0 && req.Operator != domain.ExprCountOf && req.Operator != domain.ExprPercentageOf {
+		return nil, fmt.Errorf("%s.approvers: not applicable to %q expressions", path, req.Operator)
+	}
+	if req.Count != 0 && req.Operator != domain.ExprCountOf {
+		return nil, fmt.Errorf("%s.count: not applicable to %q expressions", path, req.Operator)
+	}
+	if req.Percentage != 0 && req.Operator != domain.ExprPercentageOf {
+		return nil, fmt.Errorf("%s.percentage: not applicable to %q expressions", path, req.Operator)
+	}
+	if req.Amount != 0 && req.Operator != domain.ExprAmountAbove {
+		return nil, fmt.Errorf("%s.amount: not applicable to %q expressions", path, req.Operator)
+	}
+
+	switch req.Operator {
+	case domain.ExprAnd, domain.ExprOr:
+		if len(req.Conditions) < 2 {
+			return nil, fmt.Errorf("%s.conditions: %q expressions need at least two conditions", path, req.Operator)
+		}
+		for i := range req.Conditions {
+			condition, err := parseExpression(&req.Conditions[i], fmt.Sprintf("%s.conditions[%d]", path, i), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			expr.Conditions = append(expr.Conditions, *condition)
+		}
+
+	case domain.ExprSpecificApprover:
+		if req.ApproverID == "" {
+			return nil, fmt.Errorf("%s.approver_id: required for specific_approver expressions", path)
+		}
+		approverID, err := primitive.ObjectIDFromHex(req.ApproverID)
+		if err != nil {
+			return nil, fmt.Errorf("%s.approver_id: invalid approver ID %q", path, req.ApproverID)
+		}
+		expr.ApproverID = &approverID
+
+	case domain.ExprCountOf, domain.ExprPercentageOf:
+		approvers, err := parseObjectIDs(req.Approvers, path+".approvers")
+		if err != nil {
+			return nil, err
+		}
+		if len(approvers) == 0 {
+			return nil, fmt.Errorf("%s.approvers: at least one approver is required", path)
+		}
+		for i, approverID := range approvers {
+			if containsObjectID(approvers[:i], approverID) {
+				return nil, fmt.Errorf("%s.approvers[%d]: approver %s is listed twice", path, i, approverID.Hex())
+			}
+		}
+		expr.Approvers = approvers
+
+		if req.Operator == domain.ExprCountOf {
+			if req.Count < 1 || req.Count > len(approvers) {
+				return nil, fmt.Errorf("%s.count: must be between 1 and %d, got %d", path, len(approvers), req.Count)
+			}
+			expr.Count = req.Count
+		} else {
+			if req.Percentage <= 0 || req.Percentage > 100 {
+				return nil, fmt.Errorf("%s.percentage: must be greater than 0 and at most 100, got %g", path, req.Percentage)
+			}
+			expr.Percentage = req.Percentage
+		}
+
+	case domain.ExprDirectManager:
+		// No operands, the manager is resolved from the submitter
+
+	case domain.ExprAmountAbove:
+		if req.Amount < 0 {
+			return nil, fmt.Errorf("%s.amount: cannot be negative", path)
+		}
+		expr.Amount = req.Amount
+
+	case "":
+		return nil, fmt.Errorf("%s.operator: required", path)
+
+	default:
+		return nil, fmt.Errorf("%s.operator: unknown operator %q", path, req.Operator)
+	}
+
+	return expr, nil
+}
+
+// expressionApprovers returns every approver named anywhere in the expression
+func expressionApprovers(expr *domain.ApprovalExpression) []primitive.ObjectID {
+	var approvers []primitive.ObjectID
+	if expr.ApproverID != nil {
+		approvers = append(approvers, *expr.ApproverID)
+	}
+	approvers = append(approvers, expr.Approvers...)
+	for i := range expr.Conditions {
+		approvers = append(approvers, expressionApprovers(&expr.Conditions[i])...)
+	}
+	return approvers
+}
+
+// requiresApproval reports whether the expression depends on any approver,
+// an expression on amounts alone could never be acted upon
+func requiresApproval(expr *domain.ApprovalExpression) bool {
+	switch expr.Operator {
+	case domain.ExprAnd, domain.ExprOr:
+		for i := range expr.Conditions {
+			if requiresApproval(&expr.Conditions[i]) {
+				return true
+			}
+		}
+		return false
+	case domain.ExprAmountAbove:
+		return false
+	default:
+		return true
+	}
+}
+
+// decision is the three-valued outcome of an expression
+type decision int
+
+const (
+	undecided decision = iota
+	decidedTrue
+	decidedFalse
+)
+
+// expressionInput is what an expression is evaluated against. When planning,
+// no approvals exist yet and every approver is assumed to be askable.
+type expressionInput struct {
+	expense   *domain.Expense
+	managerID *primitive.ObjectID
+	approved  map[primitive.ObjectID]bool
+	open      map[primitive.ObjectID]bool
+	planning  bool
+	excluded  *primitive.ObjectID // Approver whose vote can never count
+}
+
+// newExpressionInput indexes approvals by the approver they count for
+func newExpressionInput(expense *domain.Expense, managerID *primitive.ObjectID, approvals []*domain.Approval) *expressionInput {
+	in := &expressionInput{
+		expense:   expense,
+		managerID: managerID,
+		approved:  make(map[primitive.ObjectID]bool),
+		open:      make(map[primitive.ObjectID]bool),
+	}
+	for _, approval := range approvals {
+		approverID := effectiveApproverID(approval)
+		switch approval.Status {
+		case domain.ApprovalApproved:
+			in.approved[approverID] = true
+		case domain.ApprovalPending, domain.ApprovalQueued:
+			in.open[approverID] = true
+		}
+	}
+	return in
+}
+
+// vote returns whether the approver approved, may still approve, or cannot
+func (in *expressionInput) vote(approverID primitive.ObjectID) decision {
+	if in.approved[approverID] {
+		return decidedTrue
+	}
+	if in.excluded != nil && *in.excluded == approverID {
+		return decidedFalse
+	}
+	if in.planning || in.open[approverID] {
+		return undecided
+	}
+	return decidedFalse
+}
+
+// evaluateExpression decides the expression as far as the input allows
+func evaluateExpression(expr *domain.ApprovalExpression, in *expressionInput) decision {
+	switch expr.Operator {
+	case domain.ExprAnd:
+		result := decidedTrue
+		for i := range expr.Conditions {
+			switch evaluateExpression(&expr.Conditions[i], in) {
+			case decidedFalse:
+				return decidedFalse
+			case undecided:
+				result = undecided
+			}
+		}
+		return result
+
+	case domain.ExprOr:
+		result := decidedFalse
+		for i := range expr.Conditions {
+			switch evaluateExpression(&expr.Conditions[i], in) {
+			case decidedTrue:
+				return decidedTrue
+			case undecided:
+				result = undecided
+			}
+		}
+		return result
+
+	case domain.ExprSpecificApprover:
+		return in.vote(*expr.ApproverID)
+
+	case domain.ExprDirectManager:
+		if in.managerID == nil {
+			return decidedFalse
+		}
+		return in.vote(*in.managerID)
+
+	case domain.ExprCountOf:
+		return countVotes(expr.Approvers, expr.Count, in)
+
+	case domain.ExprPercentageOf:
+		required := int(math.Ceil(expr.Percentage / 100 * float64(len(expr.Approvers))))
+		if required < 1 {
+			required = 1
+		}
+		return countVotes(expr.Approvers, required, in)
+
+	case domain.ExprAmountAbove:
+		if in.expense.ConvertedAmount > expr.Amount {
+			return decidedTrue
+		}
+		return decidedFalse
+	}
+
+	return decidedFalse
+}
+
+// countVotes decides whether at least required of the approvers approved
+func countVotes(approvers []primitive.ObjectID, required int, in *expressionInput) decision {
+	approved, possible := 0, 0
+	for _, approverID := range approvers {
+		switch in.vote(approverID) {
+		case decidedTrue:
+			approved++
+			possible++
+		case undecided:
+			possible++
+		}
+	}
+
+	if approved >= required {
+		return decidedTrue
+	}
+	if possible < required {
+		return decidedFalse
+	}
+	return undecided
+}
+
+// plannedApprovers returns the approvers to ask for the expense: those named
+// in branches that can still change the outcome, in expression order
+func plannedApprovers(expr *domain.ApprovalExpression, in *expressionInput) []primitive.ObjectID {
+	if evaluateExpression(expr, in) != undecided {
+		return nil
+	}
+
+	switch expr.Operator {
+	case domain.ExprAnd, domain.ExprOr:
+		var approvers []primitive.ObjectID
+		for i := range expr.Conditions {
+			for _, approverID := range plannedApprovers(&expr.Conditions[i], in) {
+				if !containsObjectID(approvers, approverID) {
+					approvers = append(approvers, approverID)
+				}
+			}
+		}
+		return approvers
+	case domain.ExprSpecificApprover:
+		return []primitive.ObjectID{*expr.ApproverID}
+	case domain.ExprDirectManager:
+		return []primitive.ObjectID{*in.managerID}
+	case domain.ExprCountOf, domain.ExprPercentageOf:
+		return expr.Approvers
+	}
+
+	return nil
+}
+
+// describeExpression renders the expression as a readable condition
+func describeExpression(expr *domain.ApprovalExpression, top bool) string {
+	switch expr.Operator {
+	case domain.ExprAnd, domain.ExprOr:
+		parts := make([]string, 0, len(expr.Conditions))
+		for i := range expr.Conditions {
+			parts = append(parts, describeExpression(&expr.Conditions[i], false))
+		}
+		joined := strings.Join(parts, " "+strings.ToUpper(string(expr.Operator))+" ")
+		if top {
+			return joined
+		}
+		return "(" + joined + ")"
+	case domain.ExprSpecificApprover:
+		return fmt.Sprintf("approver %s approves", expr.ApproverID.Hex())
+	case domain.ExprCountOf:
+		return fmt.Sprintf("at least %d of %s approve", expr.Count, formatApprovers(expr.Approvers))
+	case domain.ExprPercentageOf:
+		return fmt.Sprintf("at least %g%% of %s approve", expr.Percentage, formatApprovers(expr.Approvers))
+	case domain.ExprDirectManager:
+		return "the submitter's direct manager approves"
+	case domain.ExprAmountAbove:
+		return fmt.Sprintf("amount is above %.2f", expr.Amount)
+	}
+	return string(expr.Operator)
+}
+
+// formatApprovers renders a list of approver IDs
+func formatApprovers(approvers []primitive.ObjectID) string {
+	ids := make([]string, 0, len(approvers))
+	for _, approverID := range approvers {
+		ids = append(ids, approverID.Hex())
+	}
+	return "[" + strings.Join(ids, ", ") + "]"
+}
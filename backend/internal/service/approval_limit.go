@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"expensio-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// withinApprovalLimit reports whether the user may sign off amount. Users
+// without an approval limit are not restricted.
+func withinApprovalLimit(user *domain.User, amount float64) bool {
+	return user.ApprovalLimit == nil || *user.ApprovalLimit >= amount
+}
+
+// wouldFinalize reports whether approving approval would approve the expense
+func (s *ApprovalService) wouldFinalize(ctx context.Context, expense *domain.Expense, approval *domain.Approval) (bool, error) {
+	approvals, err := s.approvalRepo.FindByExpenseID(ctx, expense.ID.Hex())
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch approvals: %w", err)
+	}
+
+	current := currentApprovals(expense, approvals)
+	simulated := make([]*domain.Approval, len(current))
+	for i, a := range current {
+		if a.ID == approval.ID {
+			approved := *a
+			approved.Status = domain.ApprovalApproved
+			a = &approved
+		}
+		simulated[i] = a
+	}
+
+	return s.checkAutoApproval(ctx, expense, simulated)
+}
+
+// escalateOverLimit records the approver's sign-off without finalizing it and
+// hands the approval to the next manager up the approver's chain whose limit
+// covers the amount
+func (s *ApprovalService) escalateOverLimit(ctx context.Context, expense *domain.Expense, approval *domain.Approval, approver *domain.User, req *ApprovalActionRequest, caches approvalCaches) error {
+	approvals, err := s.approvalRepo.FindByExpenseID(ctx, expense.ID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to fetch approvals: %w", err)
+	}
+
+	targetID, err := s.limitEscalationTarget(ctx, expense, approver, approvals)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	approval.Comments = req.Comments
+	approval.ApprovedAt = &now
+	reason := fmt.Sprintf("approved by %s, but amount %.2f exceeds their approval limit of %.2f",
+		approver.ID.Hex(), expense.ConvertedAmount, *approver.ApprovalLimit)
+	if _, err := s.handOverApproval(ctx, expense, approval, targetID, reason, now); err != nil {
+		return err
+	}
+
+	fmt.Printf("💳 Amount %.2f of expense %s exceeds the limit of %s, escalated to %s\n",
+		expense.ConvertedAmount, expense.ID.Hex(), approver.ID.Hex(), targetID.Hex())
+
+	companyID := expense.CompanyID.Hex()
+	caches.add(companyID, approval.ApproverID.Hex())
+	caches.add(companyID, targetID.Hex())
+
+	return nil
+}
+
+// limitEscalationTarget walks up the approver's managers to the first one
+// whose limit covers the amount, falling back to the company admin. Nobody
+// who is the submitter or already has a pending approval on the expense is
+// picked; managers whose own level is still queued can take it over.
+func (s *ApprovalService) limitEscalationTarget(ctx context.Context, expense *domain.Expense, approver *domain.User, approvals []*domain.Approval) (primitive.ObjectID, error) {
+	eligible := func(user *domain.User) bool {
+		return user.IsActive && user.CompanyID == expense.CompanyID && user.ID != expense.UserID &&
+			withinApprovalLimit(user, expense.ConvertedAmount) && !hasPendingApproval(approvals, user.ID)
+	}
+
+	visited := map[primitive.ObjectID]bool{approver.ID: true}
+	current := approver
+	for hop := 0; hop < maxManagerChainLevels && current.ManagerID != nil; hop++ {
+		managerID := *current.ManagerID
+		if visited[managerID] {
+			break
+		}
+		visited[managerID] = true
+
+		manager, err := s.userRepo.FindByID(ctx, managerID.Hex())
+		if err != nil {
+			break
+		}
+		if eligible(manager) {
+			return managerID, nil
+		}
+		current = manager
+	}
+
+	if company, err := s.companyRepo.FindByID(ctx, expense.CompanyID.Hex()); err == nil && !visited[company.AdminUserID] {
+		admin, err := s.userRepo.FindByID(ctx, company.AdminUserID.Hex())
+		if err == nil && eligible(admin) {
+			return admin.ID, nil
+		}
+	}
+
+	return primitive.NilObjectID, fmt.Errorf("amount %.2f exceeds your approval limit of %.2f and nobody above you can approve it",
+		expense.ConvertedAmount, *approver.ApprovalLimit)
+}
+
+// hasPendingApproval reports whether the approver is deciding on the expense now
+func hasPendingApproval(approvals []*domain.Approval, approverID primitive.ObjectID) bool {
+	for _, approval := range approvals {
+		if approval.ApproverID == approverID && approval.Status == domain.ApprovalPending {
+			return true
+		}
+	}
+	return false
+}
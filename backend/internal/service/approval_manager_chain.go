@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"expensio-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxManagerChainLevels bounds how far a manager chain rule may walk up
+const maxManagerChainLevels = 10
+
+// routingError reports an expense that cannot be routed because the company
+// setup is broken, e.g. a cycle in the manager hierarchy. It must surface to
+// the submitter instead of leaving the expense without approvers.
+type routingError struct {
+	message string
+}
+
+func (e *routingError) Error() string {
+	return e.message
+}
+
+// isRoutingError reports whether err comes from a broken approval route
+func isRoutingError(err error) bool {
+	var routing *routingError
+	return errors.As(err, &routing)
+}
+
+// planManagerChain asks the submitter's managers one level after another
+func (s *ApprovalService) planManagerChain(ctx context.Context, expense *domain.Expense, plan *ApprovalPlan, chain *domain.ManagerChain) error {
+	managerIDs, err := s.resolveManagerChain(ctx, expense, chain)
+	if err != nil {
+		return err
+	}
+
+	s.planApprovalChain(ctx, expense, plan, managerIDs, true)
+	plan.Decision = describeManagerChain(chain, len(managerIDs))
+	return nil
+}
+
+// resolveManagerChain walks up User.ManagerID from the submitter and returns
+// the managers to ask, closest first. It stops after chain.Levels managers or,
+// with UntilLimit, at the first manager whose approval limit covers the amount.
+func (s *ApprovalService) resolveManagerChain(ctx context.Context, expense *domain.Expense, chain *domain.ManagerChain) ([]primitive.ObjectID, error) {
+	current, err := s.userRepo.FindByID(ctx, expense.UserID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("submitter %s not found", expense.UserID.Hex())
+	}
+
+	maxLevels := chain.Levels
+	if maxLevels == 0 {
+		maxLevels = maxManagerChainLevels
+	}
+
+	visited := map[primitive.ObjectID]bool{current.ID: true}
+	var managerIDs []primitive.ObjectID
+	for len(managerIDs) < maxLevels {
+		if current.ManagerID == nil {
+			if chain.UntilLimit {
+				return nil, &routingError{message: fmt.Sprintf("manager chain ends at user %s before reaching an approval limit of %.2f",
+					current.ID.Hex(), expense.ConvertedAmount)}
+			}
+			return nil, &routingError{message: fmt.Sprintf("manager chain ends at user %s after %d of %d levels",
+				current.ID.Hex(), len(managerIDs), chain.Levels)}
+		}
+
+		managerID := *current.ManagerID
+		if visited[managerID] {
+			return nil, &routingError{message: fmt.Sprintf("manager hierarchy has a cycle: user %s reports to %s, who is already in the chain",
+				current.ID.Hex(), managerID.Hex())}
+		}
+		visited[managerID] = true
+
+		manager, err := s.userRepo.FindByID(ctx, managerID.Hex())
+		if err != nil || manager.CompanyID != expense.CompanyID {
+			return nil, &routingError{message: fmt.Sprintf("manager %s of user %s not found", managerID.Hex(), current.ID.Hex())}
+		}
+		if !manager.IsActive {
+			return nil, &routingError{message: fmt.Sprintf("manager %s of user %s is inactive", managerID.Hex(), current.ID.Hex())}
+		}
+
+		managerIDs = append(managerIDs, managerID)
+		if chain.UntilLimit && withinApprovalLimit(manager, expense.ConvertedAmount) {
+			return managerIDs, nil
+		}
+		current = manager
+	}
+
+	if chain.UntilLimit {
+		return nil, &routingError{message: fmt.Sprintf("no manager within %d levels has an approval limit covering %.2f",
+			maxLevels, expense.ConvertedAmount)}
+	}
+	return managerIDs, nil
+}
+
+// describeManagerChain describes the decision of a manager chain rule
+func describeManagerChain(chain *domain.ManagerChain, count int) string {
+	if chain.UntilLimit {
+		return fmt.Sprintf("the submitter's managers approve in order up to the first whose approval limit covers the amount (%d levels)", count)
+	}
+	return fmt.Sprintf("the submitter's %d nearest managers approve in order", count)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/notifier"
+)
+
+// notifyAssigned tells the approver that the approval now waits for them and
+// sends along an approve and a reject link. The approval is assigned either
+// way, so failures are logged rather than returned.
+func (s *ApprovalService) notifyAssigned(ctx context.Context, expense *domain.Expense, approval *domain.Approval) {
+	approver, err := s.userRepo.FindByID(ctx, approval.ApproverID.Hex())
+	if err != nil {
+		return
+	}
+
+	submitterName := "A colleague"
+	if submitter, err := s.userRepo.FindByID(ctx, expense.UserID.Hex()); err == nil {
+		submitterName = submitter.FirstName + " " + submitter.LastName
+	}
+
+	var links []*ActionLink
+	for _, action := range []BulkAction{BulkApprove, BulkReject} {
+		link, err := s.mintActionLink(approval, action)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to mint action link for approval %s: %v\n", approval.ID.Hex(), err)
+			links = nil
+			break
+		}
+		links = append(links, link)
+	}
+
+	if err := s.notifier.Send(ctx, composeApprovalRequest(approver, submitterName, expense, links)); err != nil {
+		fmt.Printf("⚠️  Failed to notify %s of approval %s: %v\n", approver.ID.Hex(), approval.ID.Hex(), err)
+	}
+}
+
+// composeApprovalRequest asks the approver to decide on the expense, with the
+// action links when they could be minted
+func composeApprovalRequest(approver *domain.User, submitterName string, expense *domain.Expense, links []*ActionLink) *notifier.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hello %s,\n\n", approver.FirstName)
+	fmt.Fprintf(&body, "%s submitted an expense for your approval:\n\n", submitterName)
+	fmt.Fprintf(&body, "- %.2f %s, %s (%s)\n\n", expense.Amount, expense.Currency, expense.Category, expense.Description)
+
+	for _, link := range links {
+		label := "Approve"
+		if link.Action == BulkReject {
+			label = "Reject"
+		}
+		fmt.Fprintf(&body, "%s: %s\n", label, link.URL)
+	}
+	if len(links) > 0 {
+		fmt.Fprintf(&body, "\nEach link shows the expense before you confirm and works once, until %s.\n",
+			links[0].ExpiresAt.Format("2006-01-02 15:04 MST"))
+	}
+	body.WriteString("You can also decide in your pending approvals.\n")
+
+	return &notifier.Message{
+		To:      approver.Email,
+		Subject: fmt.Sprintf("Expense of %.2f %s waiting for your approval", expense.Amount, expense.Currency),
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/cache"
+)
+
+// RequestChangesByApprovalID sends the expense back to its submitter for
+// changes. The comment telling them what to fix is mandatory.
+func (s *ApprovalService) RequestChangesByApprovalID(ctx context.Context, approvalID, approverID string, req *ApprovalActionRequest) error {
+	if strings.TrimSpace(req.Comments) == "" {
+		return fmt.Errorf("comments are required when requesting changes")
+	}
+
+	approval, expense, err := s.loadApprovalForAction(ctx, approvalID, approverID, "request changes on")
+	if err != nil {
+		return err
+	}
+
+	if err := ensureLevelReached(expense, approval); err != nil {
+		return err
+	}
+
+	before := auditSnapshot(approval)
+	now := time.Now()
+	approval.Status = domain.ApprovalChangesRequested
+	approval.Comments = req.Comments
+	approval.ApprovedAt = &now
+	if err := s.claimApproval(ctx, approval, domain.ApprovalPending); err != nil {
+		return err
+	}
+	s.recordApproval(ctx, expense, approval, domain.AuditApprovalChangesRequested, before)
+
+	err = s.expenseRepo.TransitionStatus(ctx, expense, domain.StatusPending, domain.StatusChangesRequested)
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		s.invalidateApprovalCaches(expense.CompanyID.Hex(), approval.ApproverID.Hex())
+		return s.concurrentDecisionError(ctx, expense, domain.StatusChangesRequested)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to request changes: %w", err)
+	}
+
+	fmt.Printf("✏️  Changes requested on expense %s by %s\n", expense.ID.Hex(), approverID)
+
+	s.settleReport(ctx, expense)
+
+	s.invalidateApprovalCaches(expense.CompanyID.Hex(), approval.ApproverID.Hex())
+	_ = cache.DeletePattern(fmt.Sprintf("expenses:user:%s:*", expense.UserID.Hex()))
+
+	return nil
+}
+
+// ResubmitExpense puts an expense with requested changes back into approval.
+// Under the restart policy of its rule the chain starts over in a new round;
+// under resume only the approvers who requested changes are asked again.
+func (s *ApprovalService) ResubmitExpense(ctx context.Context, expense *domain.Expense) error {
+	if expense.Status != domain.StatusChangesRequested {
+		return fmt.Errorf("cannot resubmit expense that is %s", expense.Status)
+	}
+
+	approvals, err := s.approvalRepo.FindByExpenseID(ctx, expense.ID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to fetch approvals: %w", err)
+	}
+
+	// The rule the expense was routed under decides, not the current one
+	rule := expense.ApprovalRule
+	if rule != nil && rule.ResubmissionPolicy == domain.ResubmissionResume {
+		if changeRequests := changeRequestsOf(expense, approvals); len(changeRequests) > 0 {
+			if err := s.markResubmitted(ctx, expense); err != nil {
+				return err
+			}
+			if err := s.resumeApprovals(ctx, expense, changeRequests); err != nil {
+				return err
+			}
+			fmt.Printf("▶️  Resumed approval of expense %s with %d approver(s)\n", expense.ID.Hex(), len(changeRequests))
+			return nil
+		}
+	}
+
+	// Plan before touching anything so a broken route leaves the expense as is
+	plan, err := s.planApprovals(ctx, expense, rule)
+	if err != nil {
+		return fmt.Errorf("failed to plan approvals: %w", err)
+	}
+	if err := s.markResubmitted(ctx, expense); err != nil {
+		return err
+	}
+
+	return s.startApprovalRound(ctx, expense, plan)
+}
+
+// markResubmitted moves the expense back to pending
+func (s *ApprovalService) markResubmitted(ctx context.Context, expense *domain.Expense) error {
+	err := s.transitionExpense(ctx, expense, domain.StatusChangesRequested, domain.StatusPending, domain.AuditExpenseResubmitted)
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return fmt.Errorf("expense was already resubmitted by a concurrent request")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resubmit expense: %w", err)
+	}
+	return nil
+}
+
+// changeRequestsOf returns the change requests of the expense's current round
+func changeRequestsOf(expense *domain.Expense, approvals []*domain.Approval) []*domain.Approval {
+	var changeRequests []*domain.Approval
+	for _, approval := range approvals {
+		if approval.Round == expense.ApprovalRound && approval.Status == domain.ApprovalChangesRequested {
+			changeRequests = append(changeRequests, approval)
+		}
+	}
+	return changeRequests
+}
+
+// resumeApprovals reopens the change requests as pending approvals for the
+// same approvers. Approvals already given stay valid.
+func (s *ApprovalService) resumeApprovals(ctx context.Context, expense *domain.Expense, changeRequests []*domain.Approval) error {
+	now := time.Now()
+	for _, approval := range changeRequests {
+		reopened := &domain.Approval{
+			ExpenseID:    expense.ID,
+			ApproverID:   approval.ApproverID,
+			OnBehalfOfID: approval.OnBehalfOfID,
+			DelegationID: approval.DelegationID,
+			Level:        approval.Level,
+			Round:        approval.Round,
+			Status:       domain.ApprovalPending,
+			AssignedAt:   &now,
+		}
+		if err := s.approvalRepo.Create(ctx, reopened); err != nil {
+			return fmt.Errorf("failed to reopen approval: %w", err)
+		}
+
+		s.invalidateApprovalCaches(expense.CompanyID.Hex(), approval.ApproverID.Hex())
+		s.notifyAssigned(ctx, expense, reopened)
+	}
+
+	return nil
+}
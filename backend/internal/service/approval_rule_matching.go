@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"expensio-backend/internal/domain"
+)
+
+// matchingRule returns the rule that applies to the expense, see selectRule
+func (s *ApprovalService) matchingRule(ctx context.Context, expense *domain.Expense) (*domain.ApprovalRule, error) {
+	submitter, err := s.userRepo.FindByID(ctx, expense.UserID.Hex())
+	if err != nil {
+		// Criteria on the submitter cannot match without them
+		submitter = nil
+	}
+	return s.selectRule(ctx, expense, submitter)
+}
+
+// selectRule returns the company's first active rule, by priority, whose
+// criteria match the expense. The company default rule applies when none
+// does; nil means the company has neither.
+func (s *ApprovalService) selectRule(ctx context.Context, expense *domain.Expense, submitter *domain.User) (*domain.ApprovalRule, error) {
+	rules, err := s.approvalRuleRepo.FindActiveByCompanyID(ctx, expense.CompanyID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch approval rules: %w", err)
+	}
+
+	var fallback *domain.ApprovalRule
+	for _, rule := range rules {
+		if rule.IsDefault {
+			if fallback == nil {
+				fallback = rule
+			}
+			continue
+		}
+		if ruleMatches(rule.Criteria, expense, submitter) {
+			return rule, nil
+		}
+	}
+
+	return fallback, nil
+}
+
+// ruleMatches reports whether the expense meets every set criterion. Category
+// criteria are checked per line of an itemized expense, see matchedAmount.
+func ruleMatches(criteria *domain.RuleCriteria, expense *domain.Expense, submitter *domain.User) bool {
+	if criteria == nil {
+		return true
+	}
+
+	amount, ok := matchedAmount(criteria, expense)
+	if !ok {
+		return false
+	}
+	if criteria.MinAmount != nil && amount < *criteria.MinAmount {
+		return false
+	}
+	if criteria.MaxAmount != nil && amount >= *criteria.MaxAmount {
+		return false
+	}
+	if len(criteria.Currencies) > 0 && !containsValue(criteria.Currencies, expense.Currency) {
+		return false
+	}
+	if len(criteria.SubmitterRoles) > 0 && (submitter == nil || !containsValue(criteria.SubmitterRoles, submitter.Role)) {
+		return false
+	}
+	if len(criteria.Departments) > 0 && (submitter == nil || !containsValue(criteria.Departments, submitter.Department)) {
+		return false
+	}
+
+	return true
+}
+
+// matchedAmount returns the part of the expense in the criteria's categories,
+// which the amount criteria apply to. A rule on meals matches a hotel bill with
+// a meals line, and its amounts are compared to the meals lines only. ok is
+// false when no part of the expense is in the categories.
+func matchedAmount(criteria *domain.RuleCriteria, expense *domain.Expense) (amount float64, ok bool) {
+	if len(criteria.Categories) == 0 {
+		return expense.ConvertedAmount, true
+	}
+
+	for category, categoryAmount := range categoryAmounts(expense) {
+		if containsValue(criteria.Categories, category) {
+			amount += categoryAmount
+			ok = true
+		}
+	}
+	return amount, ok
+}
+
+// containsValue reports whether value is in values
+func containsValue[T comparable](values []T, value T) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,645 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/validator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ApprovalRuleService struct {
+	approvalRuleRepo domain.ApprovalRuleRepository
+	userRepo         domain.UserRepository
+	companyRepo      domain.CompanyRepository
+	auditService     *AuditService
+	cfg              *config.Config
+}
+
+// NewApprovalRuleService creates a new approval rule service
+func NewApprovalRuleService(
+	approvalRuleRepo domain.ApprovalRuleRepository,
+	userRepo domain.UserRepository,
+	companyRepo domain.CompanyRepository,
+	auditService *AuditService,
+	cfg *config.Config,
+) *ApprovalRuleService {
+	return &ApprovalRuleService{
+		approvalRuleRepo: approvalRuleRepo,
+		userRepo:         userRepo,
+		companyRepo:      companyRepo,
+		auditService:     auditService,
+		cfg:              cfg,
+	}
+}
+
+type ApprovalRuleRequest struct {
+	Name                string                     `json:"name"`
+	Type                domain.ApprovalRuleType    `json:"type"`
+	Priority            int                        `json:"priority"`
+	Criteria            *domain.RuleCriteria       `json:"criteria,omitempty"`
+	IsDefault           bool                       `json:"is_default"`
+	SequentialApprovers []string                   `json:"sequential_approvers,omitempty"`
+	PercentageRequired  *float64                   `json:"percentage_required,omitempty"`
+	SpecificApproverID  *string                    `json:"specific_approver_id,omitempty"`
+	MinimumApprovals    int                        `json:"minimum_approvals"`
+	MaximumApprovals    int                        `json:"maximum_approvals"`
+	AllowedApprovers    []string                   `json:"allowed_approvers,omitempty"`
+	AmountThresholds    []AmountThresholdRequest   `json:"amount_thresholds,omitempty"`
+	Expression          *ApprovalExpressionRequest `json:"expression,omitempty"`          // Hybrid rules only
+	ManagerChain        *domain.ManagerChain       `json:"manager_chain,omitempty"`       // Manager chain rules only
+	ResubmissionPolicy  domain.ResubmissionPolicy  `json:"resubmission_policy,omitempty"` // Defaults to restart
+	IsActive            *bool                      `json:"is_active,omitempty"`           // Only used on create, defaults to true
+}
+
+type AmountThresholdRequest struct {
+	MinAmount         float64  `json:"min_amount"`
+	MaxAmount         float64  `json:"max_amount"`
+	RequiredApprovers []string `json:"required_approvers"`
+}
+
+// CreateRule creates a new approval rule for the company (Admin only)
+func (s *ApprovalRuleService) CreateRule(ctx context.Context, companyID string, req *ApprovalRuleRequest) (*domain.ApprovalRule, error) {
+	companyObjID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID")
+	}
+
+	rule := &domain.ApprovalRule{
+		CompanyID: companyObjID,
+		Version:   1,
+		IsActive:  true,
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := s.applyRequest(ctx, rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.approvalRuleRepo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create approval rule: %w", err)
+	}
+
+	if rule.IsDefault {
+		if err := s.makeDefaultRule(ctx, rule); err != nil {
+			return nil, err
+		}
+	}
+
+	s.auditService.Record(ctx, rule.CompanyID, domain.AuditRuleCreated, auditEntityRule, rule.ID, nil, rule)
+
+	return rule, nil
+}
+
+// GetRules retrieves all approval rules of the company
+func (s *ApprovalRuleService) GetRules(ctx context.Context, companyID string) ([]*domain.ApprovalRule, error) {
+	rules, err := s.approvalRuleRepo.FindAllByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch approval rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetRule retrieves an approval rule, scoped to the caller's company
+func (s *ApprovalRuleService) GetRule(ctx context.Context, companyID, ruleID string) (*domain.ApprovalRule, error) {
+	rule, err := s.approvalRuleRepo.FindByID(ctx, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf("approval rule not found")
+	}
+
+	// Never reveal rules of other companies
+	if rule.CompanyID.Hex() != companyID {
+		return nil, fmt.Errorf("approval rule not found")
+	}
+
+	return rule, nil
+}
+
+// UpdateRule replaces the configuration of an approval rule (Admin only)
+func (s *ApprovalRuleService) UpdateRule(ctx context.Context, companyID, ruleID string, req *ApprovalRuleRequest) (*domain.ApprovalRule, error) {
+	rule, err := s.GetRule(ctx, companyID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditSnapshot(rule)
+	wasDefault := rule.IsDefault
+	if err := s.applyRequest(ctx, rule, req); err != nil {
+		return nil, err
+	}
+
+	// Expenses in flight keep the version they were routed under
+	rule.Version++
+	if err := s.approvalRuleRepo.Update(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update approval rule: %w", err)
+	}
+
+	switch {
+	case rule.IsDefault && !wasDefault:
+		if err := s.makeDefaultRule(ctx, rule); err != nil {
+			return nil, err
+		}
+	case !rule.IsDefault && wasDefault:
+		if err := s.detachFromCompany(ctx, rule); err != nil {
+			return nil, err
+		}
+	}
+
+	s.auditService.Record(ctx, rule.CompanyID, domain.AuditRuleUpdated, auditEntityRule, rule.ID, before, rule)
+
+	return rule, nil
+}
+
+// ActivateRule makes the rule take part in matching expenses (Admin only)
+func (s *ApprovalRuleService) ActivateRule(ctx context.Context, companyID, ruleID string) error {
+	rule, err := s.GetRule(ctx, companyID, ruleID)
+	if err != nil {
+		return err
+	}
+
+	// Re-validate in case approvers left the company since the rule was saved
+	if err := s.validateRule(ctx, rule); err != nil {
+		return err
+	}
+
+	before := auditSnapshot(rule)
+	if err := s.approvalRuleRepo.SetActive(ctx, ruleID, true); err != nil {
+		return fmt.Errorf("failed to activate approval rule: %w", err)
+	}
+	rule.IsActive = true
+
+	s.auditService.Record(ctx, rule.CompanyID, domain.AuditRuleActivated, auditEntityRule, rule.ID, before, rule)
+
+	if rule.IsDefault {
+		return s.makeDefaultRule(ctx, rule)
+	}
+	return nil
+}
+
+// DeactivateRule deactivates an approval rule (Admin only)
+func (s *ApprovalRuleService) DeactivateRule(ctx context.Context, companyID, ruleID string) error {
+	rule, err := s.GetRule(ctx, companyID, ruleID)
+	if err != nil {
+		return err
+	}
+
+	before := auditSnapshot(rule)
+	if err := s.approvalRuleRepo.SetActive(ctx, ruleID, false); err != nil {
+		return fmt.Errorf("failed to deactivate approval rule: %w", err)
+	}
+	rule.IsActive = false
+
+	s.auditService.Record(ctx, rule.CompanyID, domain.AuditRuleDeactivated, auditEntityRule, rule.ID, before, rule)
+
+	return s.detachFromCompany(ctx, rule)
+}
+
+// DeleteRule deletes an approval rule (Admin only)
+func (s *ApprovalRuleService) DeleteRule(ctx context.Context, companyID, ruleID string) error {
+	rule, err := s.GetRule(ctx, companyID, ruleID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.approvalRuleRepo.Delete(ctx, ruleID); err != nil {
+		return fmt.Errorf("failed to delete approval rule: %w", err)
+	}
+
+	s.auditService.Record(ctx, rule.CompanyID, domain.AuditRuleDeleted, auditEntityRule, rule.ID, rule, nil)
+
+	return s.detachFromCompany(ctx, rule)
+}
+
+// makeDefaultRule unmarks the company's other default rules and points
+// Company.ApprovalRuleID at rule
+func (s *ApprovalRuleService) makeDefaultRule(ctx context.Context, rule *domain.ApprovalRule) error {
+	companyID := rule.CompanyID.Hex()
+
+	if err := s.approvalRuleRepo.ClearDefaultExcept(ctx, companyID, rule.ID.Hex()); err != nil {
+		return fmt.Errorf("failed to clear previous default approval rule: %w", err)
+	}
+
+	company, err := s.companyRepo.FindByID(ctx, companyID)
+	if err != nil {
+		return fmt.Errorf("company not found")
+	}
+
+	ruleID := rule.ID
+	company.ApprovalRuleID = &ruleID
+	if err := s.companyRepo.Update(ctx, company); err != nil {
+		return fmt.Errorf("failed to update company: %w", err)
+	}
+
+	return nil
+}
+
+// detachFromCompany clears Company.ApprovalRuleID if it points at rule
+func (s *ApprovalRuleService) detachFromCompany(ctx context.Context, rule *domain.ApprovalRule) error {
+	company, err := s.companyRepo.FindByID(ctx, rule.CompanyID.Hex())
+	if err != nil {
+		return fmt.Errorf("company not found")
+	}
+
+	if company.ApprovalRuleID == nil || *company.ApprovalRuleID != rule.ID {
+		return nil
+	}
+
+	company.ApprovalRuleID = nil
+	if err := s.companyRepo.Update(ctx, company); err != nil {
+		return fmt.Errorf("failed to update company: %w", err)
+	}
+
+	return nil
+}
+
+// applyRequest copies the request onto rule and validates the result
+func (s *ApprovalRuleService) applyRequest(ctx context.Context, rule *domain.ApprovalRule, req *ApprovalRuleRequest) error {
+	sequentialApprovers, err := parseObjectIDs(req.SequentialApprovers, "sequential_approvers")
+	if err != nil {
+		return err
+	}
+
+	allowedApprovers, err := parseObjectIDs(req.AllowedApprovers, "allowed_approvers")
+	if err != nil {
+		return err
+	}
+
+	var specificApproverID *primitive.ObjectID
+	if req.SpecificApproverID != nil && *req.SpecificApproverID != "" {
+		objID, err := primitive.ObjectIDFromHex(*req.SpecificApproverID)
+		if err != nil {
+			return fmt.Errorf("invalid specific_approver_id")
+		}
+		specificApproverID = &objID
+	}
+
+	thresholds := make([]domain.AmountThreshold, 0, len(req.AmountThresholds))
+	for i, threshold := range req.AmountThresholds {
+		approvers, err := parseObjectIDs(threshold.RequiredApprovers, fmt.Sprintf("amount_thresholds[%d].required_approvers", i))
+		if err != nil {
+			return err
+		}
+		thresholds = append(thresholds, domain.AmountThreshold{
+			MinAmount:         threshold.MinAmount,
+			MaxAmount:         threshold.MaxAmount,
+			RequiredApprovers: approvers,
+		})
+	}
+
+	var expression *domain.ApprovalExpression
+	if req.Expression != nil {
+		expression, err = parseExpression(req.Expression, "expression", 1)
+		if err != nil {
+			return err
+		}
+	}
+
+	rule.Name = req.Name
+	rule.Type = req.Type
+	rule.Priority = req.Priority
+	rule.Criteria = req.Criteria
+	rule.IsDefault = req.IsDefault
+	rule.SequentialApprovers = sequentialApprovers
+	rule.PercentageRequired = req.PercentageRequired
+	rule.SpecificApproverID = specificApproverID
+	rule.MinimumApprovals = req.MinimumApprovals
+	rule.MaximumApprovals = req.MaximumApprovals
+	rule.AllowedApprovers = allowedApprovers
+	rule.AmountThresholds = thresholds
+	rule.Expression = expression
+	rule.ManagerChain = req.ManagerChain
+	rule.ResubmissionPolicy = req.ResubmissionPolicy
+
+	return s.validateRule(ctx, rule)
+}
+
+// validateRule checks that the rule fields match its type and that every
+// approver is an active manager or admin of the rule's company
+func (s *ApprovalRuleService) validateRule(ctx context.Context, rule *domain.ApprovalRule) error {
+	if err := validator.ValidateName(rule.Name, "Rule name"); err != nil {
+		return err
+	}
+	if err := validator.ValidateApprovalRuleType(string(rule.Type)); err != nil {
+		return err
+	}
+	if err := validateRuleShape(rule); err != nil {
+		return err
+	}
+	if rule.ResubmissionPolicy == "" {
+		rule.ResubmissionPolicy = domain.ResubmissionRestart
+	}
+	if rule.ResubmissionPolicy != domain.ResubmissionRestart && rule.ResubmissionPolicy != domain.ResubmissionResume {
+		return fmt.Errorf("resubmission_policy must be %q or %q", domain.ResubmissionRestart, domain.ResubmissionResume)
+	}
+
+	if rule.Priority < 0 {
+		return fmt.Errorf("priority cannot be negative")
+	}
+	if rule.IsDefault && rule.Criteria != nil {
+		return fmt.Errorf("the default rule applies to every expense no other rule matches and cannot have criteria")
+	}
+	if rule.Criteria != nil {
+		if err := validateCriteria(rule.Criteria); err != nil {
+			return err
+		}
+	}
+
+	if rule.MinimumApprovals < 0 || rule.MaximumApprovals < 0 {
+		return fmt.Errorf("minimum_approvals and maximum_approvals cannot be negative")
+	}
+	if rule.MaximumApprovals > 0 && rule.MinimumApprovals > rule.MaximumApprovals {
+		return fmt.Errorf("minimum_approvals cannot be greater than maximum_approvals")
+	}
+
+	thresholds, err := normalizeAmountThresholds(rule.AmountThresholds)
+	if err != nil {
+		return err
+	}
+	rule.AmountThresholds = thresholds
+
+	return s.validateApprovers(ctx, rule)
+}
+
+// validateCriteria checks the values of the rule's match criteria
+func validateCriteria(criteria *domain.RuleCriteria) error {
+	for i, category := range criteria.Categories {
+		if err := validator.ValidateCategory(string(category)); err != nil {
+			return fmt.Errorf("criteria.categories[%d]: %w", i, err)
+		}
+	}
+	for i, currency := range criteria.Currencies {
+		if err := validator.ValidateCurrency(currency); err != nil {
+			return fmt.Errorf("criteria.currencies[%d]: %w", i, err)
+		}
+	}
+	for i, role := range criteria.SubmitterRoles {
+		if err := validator.ValidateRole(string(role)); err != nil {
+			return fmt.Errorf("criteria.submitter_roles[%d]: %w", i, err)
+		}
+	}
+	for i, department := range criteria.Departments {
+		if department == "" {
+			return fmt.Errorf("criteria.departments[%d]: cannot be empty", i)
+		}
+	}
+	if criteria.MinAmount != nil && *criteria.MinAmount < 0 {
+		return fmt.Errorf("criteria.min_amount cannot be negative")
+	}
+	if criteria.MaxAmount != nil && *criteria.MaxAmount <= 0 {
+		return fmt.Errorf("criteria.max_amount must be greater than zero")
+	}
+	if criteria.MinAmount != nil && criteria.MaxAmount != nil && *criteria.MaxAmount <= *criteria.MinAmount {
+		return fmt.Errorf("criteria.max_amount must be greater than criteria.min_amount")
+	}
+	return nil
+}
+
+// normalizeAmountThresholds sorts the bands by MinAmount and verifies that
+// they form one contiguous range. Each band covers [MinAmount, MaxAmount);
+// a MaxAmount of 0 leaves the last band open-ended.
+func normalizeAmountThresholds(thresholds []domain.AmountThreshold) ([]domain.AmountThreshold, error) {
+	sorted := make([]domain.AmountThreshold, len(thresholds))
+	copy(sorted, thresholds)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].MinAmount < sorted[j].MinAmount
+	})
+
+	for i, threshold := range sorted {
+		if len(threshold.RequiredApprovers) == 0 {
+			return nil, fmt.Errorf("amount threshold %s requires at least one approver", formatBand(threshold))
+		}
+		if threshold.MinAmount < 0 {
+			return nil, fmt.Errorf("amount threshold %s cannot start below zero", formatBand(threshold))
+		}
+		if threshold.MaxAmount != 0 && threshold.MaxAmount <= threshold.MinAmount {
+			return nil, fmt.Errorf("amount threshold %s must have max_amount greater than min_amount", formatBand(threshold))
+		}
+		if threshold.MaxAmount == 0 && i != len(sorted)-1 {
+			return nil, fmt.Errorf("only the highest amount threshold can be open-ended (max_amount 0), but %s is followed by %s",
+				formatBand(threshold), formatBand(sorted[i+1]))
+		}
+
+		if i == 0 {
+			continue
+		}
+		previous := sorted[i-1]
+		if threshold.MinAmount < previous.MaxAmount {
+			return nil, fmt.Errorf("amount thresholds %s and %s overlap", formatBand(previous), formatBand(threshold))
+		}
+		if threshold.MinAmount > previous.MaxAmount {
+			return nil, fmt.Errorf("amount thresholds %s and %s leave a gap between %.2f and %.2f",
+				formatBand(previous), formatBand(threshold), previous.MaxAmount, threshold.MinAmount)
+		}
+	}
+
+	return sorted, nil
+}
+
+// formatBand renders a threshold band for error messages
+func formatBand(threshold domain.AmountThreshold) string {
+	if threshold.MaxAmount == 0 {
+		return fmt.Sprintf("[%.2f, ∞)", threshold.MinAmount)
+	}
+	return fmt.Sprintf("[%.2f, %.2f)", threshold.MinAmount, threshold.MaxAmount)
+}
+
+// validateRuleShape checks that only the fields used by the rule type are set
+func validateRuleShape(rule *domain.ApprovalRule) error {
+	hasSequential := len(rule.SequentialApprovers) > 0
+	hasAllowed := len(rule.AllowedApprovers) > 0
+	hasPercentage := rule.PercentageRequired != nil
+	hasSpecific := rule.SpecificApproverID != nil
+	hasExpression := rule.Expression != nil
+	hasManagerChain := rule.ManagerChain != nil
+
+	if hasExpression && rule.Type != domain.RuleTypeHybrid {
+		return notApplicable("expression", rule.Type)
+	}
+	if hasManagerChain && rule.Type != domain.RuleTypeManagerChain {
+		return notApplicable("manager_chain", rule.Type)
+	}
+
+	if hasPercentage {
+		if err := validator.ValidatePercentage(*rule.PercentageRequired, "percentage_required"); err != nil {
+			return err
+		}
+	}
+
+	switch rule.Type {
+	case domain.RuleTypeSequential:
+		if !hasSequential {
+			return fmt.Errorf("sequential rules require at least one sequential approver")
+		}
+		if hasPercentage {
+			return notApplicable("percentage_required", rule.Type)
+		}
+		if hasSpecific {
+			return notApplicable("specific_approver_id", rule.Type)
+		}
+		if hasAllowed {
+			return notApplicable("allowed_approvers", rule.Type)
+		}
+	case domain.RuleTypePercentage:
+		if !hasAllowed {
+			return fmt.Errorf("percentage rules require at least one allowed approver")
+		}
+		if !hasPercentage {
+			return fmt.Errorf("percentage rules require percentage_required")
+		}
+		if hasSequential {
+			return notApplicable("sequential_approvers", rule.Type)
+		}
+		if hasSpecific {
+			return notApplicable("specific_approver_id", rule.Type)
+		}
+	case domain.RuleTypeSpecificApprover:
+		if !hasSpecific {
+			return fmt.Errorf("specific approver rules require specific_approver_id")
+		}
+		if hasSequential {
+			return notApplicable("sequential_approvers", rule.Type)
+		}
+		if hasAllowed {
+			return notApplicable("allowed_approvers", rule.Type)
+		}
+		if hasPercentage {
+			return notApplicable("percentage_required", rule.Type)
+		}
+	case domain.RuleTypeHybrid:
+		if hasExpression {
+			// The expression replaces the fixed hybrid fields
+			switch {
+			case hasSequential:
+				return fmt.Errorf("sequential_approvers cannot be combined with expression, use conditions instead")
+			case hasAllowed:
+				return fmt.Errorf("allowed_approvers cannot be combined with expression, use conditions instead")
+			case hasPercentage:
+				return fmt.Errorf("percentage_required cannot be combined with expression, use conditions instead")
+			case hasSpecific:
+				return fmt.Errorf("specific_approver_id cannot be combined with expression, use conditions instead")
+			}
+			if !requiresApproval(rule.Expression) {
+				return fmt.Errorf("expression must depend on at least one approver condition")
+			}
+			break
+		}
+		if !hasSequential && !hasAllowed {
+			return fmt.Errorf("hybrid rules require sequential_approvers or allowed_approvers")
+		}
+		if hasAllowed && !hasPercentage {
+			return fmt.Errorf("hybrid rules with allowed_approvers require percentage_required")
+		}
+		// Hybrid rules only create approvals for the listed approvers, so the
+		// specific approver must be one of them to ever be able to act
+		if hasSpecific &&
+			!containsObjectID(rule.SequentialApprovers, *rule.SpecificApproverID) &&
+			!containsObjectID(rule.AllowedApprovers, *rule.SpecificApproverID) {
+			return fmt.Errorf("specific_approver_id must also be listed in sequential_approvers or allowed_approvers for hybrid rules")
+		}
+	case domain.RuleTypeManagerChain:
+		if !hasManagerChain {
+			return fmt.Errorf("manager chain rules require manager_chain")
+		}
+		switch {
+		case hasSequential:
+			return notApplicable("sequential_approvers", rule.Type)
+		case hasAllowed:
+			return notApplicable("allowed_approvers", rule.Type)
+		case hasPercentage:
+			return notApplicable("percentage_required", rule.Type)
+		case hasSpecific:
+			return notApplicable("specific_approver_id", rule.Type)
+		}
+		chain := rule.ManagerChain
+		if chain.Levels < 0 || chain.Levels > maxManagerChainLevels {
+			return fmt.Errorf("manager_chain.levels must be between 0 and %d", maxManagerChainLevels)
+		}
+		if chain.Levels == 0 && !chain.UntilLimit {
+			return fmt.Errorf("manager_chain requires levels or until_limit")
+		}
+	}
+
+	return nil
+}
+
+// validateApprovers verifies every approver referenced by the rule
+func (s *ApprovalRuleService) validateApprovers(ctx context.Context, rule *domain.ApprovalRule) error {
+	var approverIDs []primitive.ObjectID
+	approverIDs = append(approverIDs, rule.SequentialApprovers...)
+	approverIDs = append(approverIDs, rule.AllowedApprovers...)
+	if rule.SpecificApproverID != nil {
+		approverIDs = append(approverIDs, *rule.SpecificApproverID)
+	}
+	for _, threshold := range rule.AmountThresholds {
+		approverIDs = append(approverIDs, threshold.RequiredApprovers...)
+	}
+	if rule.Expression != nil {
+		approverIDs = append(approverIDs, expressionApprovers(rule.Expression)...)
+	}
+
+	checked := make(map[primitive.ObjectID]bool)
+	for _, approverID := range approverIDs {
+		if checked[approverID] {
+			continue
+		}
+		checked[approverID] = true
+
+		if err := s.validateApprover(ctx, rule.CompanyID, approverID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateApprover verifies that the user can act as an approver in the company
+func (s *ApprovalRuleService) validateApprover(ctx context.Context, companyID, approverID primitive.ObjectID) error {
+	approver, err := s.userRepo.FindByID(ctx, approverID.Hex())
+	if err != nil || approver.CompanyID != companyID {
+		return fmt.Errorf("approver %s does not belong to your company", approverID.Hex())
+	}
+	if !approver.IsActive {
+		return fmt.Errorf("approver %s is inactive", approverID.Hex())
+	}
+	if approver.Role != domain.RoleAdmin && approver.Role != domain.RoleManager {
+		return fmt.Errorf("approver %s must be a manager or admin", approverID.Hex())
+	}
+	return nil
+}
+
+// parseObjectIDs converts hex IDs, rejecting malformed and duplicate entries
+func parseObjectIDs(ids []string, field string) ([]primitive.ObjectID, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for i, id := range ids {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ID in %s[%d]", field, i)
+		}
+		if containsObjectID(objectIDs, objID) {
+			return nil, fmt.Errorf("duplicate ID in %s[%d]", field, i)
+		}
+		objectIDs = append(objectIDs, objID)
+	}
+	return objectIDs, nil
+}
+
+// containsObjectID reports whether id is in ids
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// notApplicable builds the error for a field that does not belong to the rule type
+func notApplicable(field string, ruleType domain.ApprovalRuleType) error {
+	return fmt.Errorf("%s is not applicable to %s rules", field, ruleType)
+}
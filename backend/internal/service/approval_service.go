@@ -2,11 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"expensio-backend/internal/config"
 	"expensio-backend/internal/domain"
 	"expensio-backend/pkg/cache"
+	"expensio-backend/pkg/cursor"
+	"expensio-backend/pkg/notifier"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ApprovalService struct {
@@ -14,6 +20,11 @@ type ApprovalService struct {
 	approvalRuleRepo domain.ApprovalRuleRepository
 	expenseRepo      domain.ExpenseRepository
 	userRepo         domain.UserRepository
+	companyRepo      domain.CompanyRepository
+	delegationRepo   domain.DelegationRepository
+	reportService    *ExpenseReportService
+	auditService     *AuditService
+	notifier         notifier.Notifier
 	cfg              *config.Config
 }
 
@@ -23,6 +34,10 @@ func NewApprovalService(
 	approvalRuleRepo domain.ApprovalRuleRepository,
 	expenseRepo domain.ExpenseRepository,
 	userRepo domain.UserRepository,
+	companyRepo domain.CompanyRepository,
+	delegationRepo domain.DelegationRepository,
+	auditService *AuditService,
+	notifier notifier.Notifier,
 	cfg *config.Config,
 ) *ApprovalService {
 	return &ApprovalService{
@@ -30,10 +45,20 @@ func NewApprovalService(
 		approvalRuleRepo: approvalRuleRepo,
 		expenseRepo:      expenseRepo,
 		userRepo:         userRepo,
+		companyRepo:      companyRepo,
+		delegationRepo:   delegationRepo,
+		auditService:     auditService,
+		notifier:         notifier,
 		cfg:              cfg,
 	}
 }
 
+// SetReportService sets the expense report service that reports decisions
+// on routing expenses are passed on to (to avoid circular dependency)
+func (s *ApprovalService) SetReportService(reportService *ExpenseReportService) {
+	s.reportService = reportService
+}
+
 type ApprovalActionRequest struct {
 	Comments string `json:"comments,omitempty"`
 }
@@ -42,32 +67,135 @@ type ApprovalActionRequest struct {
 func (s *ApprovalService) InitializeApprovals(ctx context.Context, expense *domain.Expense) error {
 	fmt.Printf("🔄 InitializeApprovals called for expense ID: %s, UserID: %s\n", expense.ID.Hex(), expense.UserID.Hex())
 
-	// Get company's approval rule
-	rule, err := s.approvalRuleRepo.FindByCompanyID(ctx, expense.CompanyID.Hex())
+	// Get the company's approval rule that matches the expense
+	rule, err := s.matchingRule(ctx, expense)
 	if err != nil {
-		fmt.Printf("⚠️  No approval rule found for company %s, using default approval\n", expense.CompanyID.Hex())
+		fmt.Printf("⚠️  Failed to select approval rule: %v\n", err)
+		rule = nil
+	}
+	if rule == nil {
+		fmt.Printf("⚠️  No approval rule matches for company %s, using default approval\n", expense.CompanyID.Hex())
+	} else {
+		fmt.Printf("✅ Matched approval rule %q type: %s (version %d)\n", rule.Name, rule.Type, rule.Version)
+	}
+
+	plan, err := s.planApprovals(ctx, expense, rule)
+	if err != nil {
+		return err
+	}
+
+	return s.applyPlan(ctx, expense, plan)
+}
+
+// validateRoute plans the approvals of the expense like InitializeApprovals
+// without creating them, to refuse an expense the policies cannot route
+func (s *ApprovalService) validateRoute(ctx context.Context, expense *domain.Expense) error {
+	rule, err := s.matchingRule(ctx, expense)
+	if err != nil {
+		rule = nil
+	}
+
+	_, err = s.planApprovals(ctx, expense, rule)
+	return err
+}
+
+// ApprovalPlan describes how an expense is routed: who is asked at which
+// level and what finalizes the decision. InitializeApprovals applies it and
+// SimulateRoute returns it without writing anything.
+type ApprovalPlan struct {
+	Rule        *domain.ApprovalRule    `json:"rule,omitempty"`
+	Threshold   *domain.AmountThreshold `json:"threshold,omitempty"`
+	AutoApprove bool                    `json:"auto_approve"`
+	AutoReject  bool                    `json:"auto_reject"`
+	Fallback    bool                    `json:"fallback,omitempty"` // The rule could not route the expense, every planned approval is required
+	Approvals   []*PlannedApproval      `json:"approvals"`
+	Decision    string                  `json:"decision"`
+
+	// Conflict-of-interest handling of the company, see approval_conflict.go
+	settings domain.CompanySettings
+	adminID  *primitive.ObjectID
+}
+
+// PlannedApproval is an approval the plan would create
+type PlannedApproval struct {
+	ApproverID   primitive.ObjectID    `json:"approver_id"`
+	OnBehalfOfID *primitive.ObjectID   `json:"on_behalf_of_id,omitempty"`
+	DelegationID *primitive.ObjectID   `json:"delegation_id,omitempty"`
+	Level        int                   `json:"level"`
+	Status       domain.ApprovalStatus `json:"status"`
+}
+
+// planApprovals works out the routing of the expense under rule, or under the
+// default manager approval when rule is nil. It only reads data.
+func (s *ApprovalService) planApprovals(ctx context.Context, expense *domain.Expense, rule *domain.ApprovalRule) (*ApprovalPlan, error) {
+	plan := &ApprovalPlan{Rule: rule, Approvals: []*PlannedApproval{}}
+	if company, err := s.companyRepo.FindByID(ctx, expense.CompanyID.Hex()); err == nil {
+		plan.settings = withDefaultSettings(company.Settings)
+		plan.adminID = &company.AdminUserID
+	} else {
+		plan.settings = withDefaultSettings(domain.CompanySettings{})
+	}
+
+	if err := s.planRule(ctx, expense, plan, rule); err != nil {
+		return plan, err
+	}
+
+	// Nobody independent of the submitter is left to ask
+	if len(plan.Approvals) == 0 && !plan.AutoApprove {
+		s.planWithoutApprovers(ctx, expense, plan)
+	}
+
+	return plan, nil
+}
+
+// planRule plans the approvals the rule asks for
+func (s *ApprovalService) planRule(ctx context.Context, expense *domain.Expense, plan *ApprovalPlan, rule *domain.ApprovalRule) error {
+	if rule == nil {
 		// If no rule exists, create a simple approval for user's manager
-		return s.createDefaultApproval(ctx, expense)
+		return s.planDefaultApproval(ctx, expense, plan)
 	}
 
-	fmt.Printf("✅ Found approval rule type: %s\n", rule.Type)
+	// Amount bands override the rule's approver list for larger expenses
+	if threshold := selectAmountThreshold(rule, expense.ConvertedAmount); threshold != nil {
+		fmt.Printf("💵 Amount %.2f falls in threshold band %.2f-%.2f\n", expense.ConvertedAmount, threshold.MinAmount, threshold.MaxAmount)
+		gated := rule.Type == domain.RuleTypeSequential
+		plan.Threshold = threshold
+		s.planApprovalChain(ctx, expense, plan, threshold.RequiredApprovers, gated)
+		plan.Decision = describeAll(len(threshold.RequiredApprovers), gated) + " of amount band " + formatBand(*threshold)
+		return nil
+	}
 
 	switch rule.Type {
 	case domain.RuleTypeSequential:
-		return s.createSequentialApprovals(ctx, expense, rule)
+		s.planApprovalChain(ctx, expense, plan, rule.SequentialApprovers, true)
+		plan.Decision = describeAll(len(rule.SequentialApprovers), true)
 	case domain.RuleTypePercentage:
-		return s.createPercentageApprovals(ctx, expense, rule)
+		s.planApprovalChain(ctx, expense, plan, rule.AllowedApprovers, false)
+		plan.Decision = describePercentage(rule)
 	case domain.RuleTypeSpecificApprover:
-		return s.createSpecificApproverApproval(ctx, expense, rule)
+		s.planApprovalChain(ctx, expense, plan, []primitive.ObjectID{*rule.SpecificApproverID}, false)
+		plan.Decision = fmt.Sprintf("approver %s approves", rule.SpecificApproverID.Hex())
 	case domain.RuleTypeHybrid:
-		return s.createHybridApprovals(ctx, expense, rule)
+		if rule.Expression != nil {
+			return s.planExpressionApprovals(ctx, expense, plan, rule.Expression)
+		}
+		// Hybrid combines sequential and percentage, all actionable at once
+		s.planApprovalChain(ctx, expense, plan, rule.SequentialApprovers, false)
+		s.planApprovalChain(ctx, expense, plan, rule.AllowedApprovers, false)
+		plan.Decision = describeHybrid(rule)
+	case domain.RuleTypeManagerChain:
+		return s.planManagerChain(ctx, expense, plan, rule.ManagerChain)
 	default:
-		return s.createDefaultApproval(ctx, expense)
+		plan.Fallback = true
+		return s.planDefaultApproval(ctx, expense, plan)
 	}
+
+	return nil
 }
 
-// createDefaultApproval creates a simple approval for user's manager
-func (s *ApprovalService) createDefaultApproval(ctx context.Context, expense *domain.Expense) error {
+// planDefaultApproval asks the submitter's manager. Without one, the
+// company's no-approver policy applies.
+func (s *ApprovalService) planDefaultApproval(ctx context.Context, expense *domain.Expense, plan *ApprovalPlan) error {
 	fmt.Printf("📝 Creating default approval for expense %s\n", expense.ID.Hex())
 
 	user, err := s.userRepo.FindByID(ctx, expense.UserID.Hex())
@@ -79,97 +207,331 @@ func (s *ApprovalService) createDefaultApproval(ctx context.Context, expense *do
 	fmt.Printf("👤 User found: %s %s (Role: %s)\n", user.FirstName, user.LastName, user.Role)
 
 	if user.ManagerID == nil {
-		fmt.Printf("⚠️  User has no manager, auto-approving expense\n")
-		// If no manager, auto-approve (for admin users)
-		expense.Status = domain.StatusApproved
-		return s.expenseRepo.Update(ctx, expense)
+		fmt.Printf("⚠️  User has no manager\n")
+		return nil
 	}
 
 	fmt.Printf("👨‍💼 Manager ID found: %s\n", user.ManagerID.Hex())
 
-	approval := &domain.Approval{
-		ExpenseID:  expense.ID,
-		ApproverID: *user.ManagerID,
-		Level:      1,
-		Status:     domain.ApprovalPending,
+	s.planApprovalChain(ctx, expense, plan, []primitive.ObjectID{*user.ManagerID}, false)
+	plan.Decision = "the submitter's manager approves"
+	return nil
+}
+
+// selectAmountThreshold returns the band of the rule that covers amount, if any
+func selectAmountThreshold(rule *domain.ApprovalRule, amount float64) *domain.AmountThreshold {
+	for i := range rule.AmountThresholds {
+		threshold := rule.AmountThresholds[i]
+		if amount >= threshold.MinAmount && (threshold.MaxAmount == 0 || amount < threshold.MaxAmount) {
+			return &threshold
+		}
 	}
+	return nil
+}
 
-	if err := s.approvalRepo.Create(ctx, approval); err != nil {
-		fmt.Printf("❌ Failed to create approval: %v\n", err)
-		return err
+// planApprovalChain adds one approval per approver at increasing levels.
+// When gated, only the first level is actionable and the others are queued
+// until the previous level is fully approved.
+func (s *ApprovalService) planApprovalChain(ctx context.Context, expense *domain.Expense, plan *ApprovalPlan, approverIDs []primitive.ObjectID, gated bool) {
+	level := 0
+	for _, approverID := range approverIDs {
+		// Submitters never approve their own expense
+		var seatID *primitive.ObjectID
+		if approverID == expense.UserID {
+			substituteID := s.selfApprovalSubstitute(ctx, expense, plan, approverIDs)
+			if substituteID == nil {
+				fmt.Printf("🚫 Skipping submitter %s as their own approver\n", approverID.Hex())
+				continue
+			}
+			fmt.Printf("🔁 Substituting %s for submitter %s as approver\n", substituteID.Hex(), approverID.Hex())
+			submitterID := approverID
+			seatID = &submitterID
+			approverID = *substituteID
+		}
+
+		level++
+		status := domain.ApprovalPending
+		if gated && level > 1 {
+			status = domain.ApprovalQueued
+		}
+
+		planned := s.planApproval(ctx, expense, approverID, level, status)
+		if seatID != nil {
+			// The substitute fills the submitter's place in the rule
+			planned.OnBehalfOfID = seatID
+		}
+		plan.Approvals = append(plan.Approvals, planned)
 	}
+}
 
-	fmt.Printf("✅ Approval created successfully! Approval for expense %s assigned to approver %s\n",
-		expense.ID.Hex(), user.ManagerID.Hex())
+// planExpressionApprovals asks every approver whose decision can still
+// change the outcome of the expression, all at once
+func (s *ApprovalService) planExpressionApprovals(ctx context.Context, expense *domain.Expense, plan *ApprovalPlan, expr *domain.ApprovalExpression) error {
+	in := newExpressionInput(expense, s.submitterManagerID(ctx, expense), nil)
+	in.planning = true
+	if plan.settings.SelfApprovalPolicy == domain.SelfApprovalSkip {
+		// The submitter's own vote can never count
+		in.excluded = &expense.UserID
+	}
 
-	// Invalidate approval caches for the approver
-	s.invalidateApprovalCaches(expense.CompanyID.Hex(), user.ManagerID.Hex())
+	switch evaluateExpression(expr, in) {
+	case decidedTrue:
+		fmt.Printf("✅ Approval expression satisfied without approvers, auto-approving expense %s\n", expense.ID.Hex())
+		plan.AutoApprove = true
+		plan.Decision = "the expression is already satisfied, the expense is approved automatically"
+		return nil
+	case decidedFalse:
+		fmt.Printf("⚠️  Approval expression cannot be satisfied for expense %s, using default approval\n", expense.ID.Hex())
+		plan.Fallback = true
+		return s.planDefaultApproval(ctx, expense, plan)
+	}
 
+	s.planApprovalChain(ctx, expense, plan, plannedApprovers(expr, in), false)
+	plan.Decision = describeExpression(expr, true)
 	return nil
 }
 
-// createSequentialApprovals creates sequential approval workflow
-func (s *ApprovalService) createSequentialApprovals(ctx context.Context, expense *domain.Expense, rule *domain.ApprovalRule) error {
-	for i, approverID := range rule.SequentialApprovers {
-		approval := &domain.Approval{
-			ExpenseID:  expense.ID,
-			ApproverID: approverID,
-			Level:      i + 1,
-			Status:     domain.ApprovalPending,
-		}
-		if err := s.approvalRepo.Create(ctx, approval); err != nil {
-			return err
-		}
+// planApproval plans a single approval, routing it to the approver's active
+// delegate when one covers the expense
+func (s *ApprovalService) planApproval(ctx context.Context, expense *domain.Expense, approverID primitive.ObjectID, level int, status domain.ApprovalStatus) *PlannedApproval {
+	planned := &PlannedApproval{
+		ApproverID: approverID,
+		Level:      level,
+		Status:     status,
 	}
-	return nil
+
+	if delegation := s.resolveDelegation(ctx, expense, approverID); delegation != nil {
+		fmt.Printf("🔀 Routing approval of %s to delegate %s\n", approverID.Hex(), delegation.DelegateID.Hex())
+		originalID := approverID
+		delegationID := delegation.ID
+		planned.ApproverID = delegation.DelegateID
+		planned.OnBehalfOfID = &originalID
+		planned.DelegationID = &delegationID
+	}
+
+	return planned
 }
 
-// createPercentageApprovals creates approvals for percentage-based rule
-func (s *ApprovalService) createPercentageApprovals(ctx context.Context, expense *domain.Expense, rule *domain.ApprovalRule) error {
-	for i, approverID := range rule.AllowedApprovers {
+// applyPlan records the plan on the expense and creates its approvals
+func (s *ApprovalService) applyPlan(ctx context.Context, expense *domain.Expense, plan *ApprovalPlan) error {
+	// Freeze the rule so later edits do not change how this expense is decided.
+	// A fallback route is decided by its approvals alone.
+	expense.ApprovalRule = plan.Rule
+	if plan.Rule != nil {
+		ruleID := plan.Rule.ID
+		expense.ApprovalRuleID = &ruleID
+	}
+	if plan.Fallback {
+		expense.ApprovalRule = nil
+	}
+	expense.ApprovalThreshold = plan.Threshold
+	before := auditSnapshot(expense)
+	switch {
+	case plan.AutoApprove:
+		expense.Status = domain.StatusApproved
+	case plan.AutoReject:
+		expense.Status = domain.StatusRejected
+	}
+	if expense.ApprovalRule != nil || plan.AutoApprove || plan.AutoReject {
+		if err := s.expenseRepo.Update(ctx, expense); err != nil {
+			return fmt.Errorf("failed to record approval routing: %w", err)
+		}
+	}
+	if plan.AutoApprove || plan.AutoReject {
+		s.auditService.Record(asSystem(ctx), expense.CompanyID, domain.AuditExpenseDecided, auditEntityExpense, expense.ID, before, expense)
+		s.settleReport(asSystem(ctx), expense)
+	}
+
+	now := time.Now()
+	for _, planned := range plan.Approvals {
 		approval := &domain.Approval{
-			ExpenseID:  expense.ID,
-			ApproverID: approverID,
-			Level:      i + 1,
-			Status:     domain.ApprovalPending,
+			ExpenseID:    expense.ID,
+			ApproverID:   planned.ApproverID,
+			OnBehalfOfID: planned.OnBehalfOfID,
+			DelegationID: planned.DelegationID,
+			Level:        planned.Level,
+			Round:        expense.ApprovalRound,
+			Status:       planned.Status,
 		}
+		if planned.Status == domain.ApprovalPending {
+			approval.AssignedAt = &now
+		}
+
 		if err := s.approvalRepo.Create(ctx, approval); err != nil {
+			fmt.Printf("❌ Failed to create approval: %v\n", err)
 			return err
 		}
+
+		if planned.Status == domain.ApprovalPending {
+			s.invalidateApprovalCaches(expense.CompanyID.Hex(), approval.ApproverID.Hex())
+			s.notifyAssigned(ctx, expense, approval)
+		}
 	}
+
+	fmt.Printf("✅ Created %d approvals for expense %s\n", len(plan.Approvals), expense.ID.Hex())
+
 	return nil
 }
 
-// createSpecificApproverApproval creates approval for specific approver
-func (s *ApprovalService) createSpecificApproverApproval(ctx context.Context, expense *domain.Expense, rule *domain.ApprovalRule) error {
-	if rule.SpecificApproverID == nil {
-		return fmt.Errorf("specific approver not configured")
+// submitterManagerID returns the manager of the expense's submitter, if any
+func (s *ApprovalService) submitterManagerID(ctx context.Context, expense *domain.Expense) *primitive.ObjectID {
+	user, err := s.userRepo.FindByID(ctx, expense.UserID.Hex())
+	if err != nil {
+		return nil
+	}
+	return user.ManagerID
+}
+
+// expressionDecision evaluates the active rule's expression against the
+// approvals, ok is false when the expense is not decided by an expression
+func (s *ApprovalService) expressionDecision(ctx context.Context, expense *domain.Expense, approvals []*domain.Approval) (result decision, ok bool) {
+	if expense.ApprovalThreshold != nil {
+		return undecided, false
 	}
 
-	approval := &domain.Approval{
-		ExpenseID:  expense.ID,
-		ApproverID: *rule.SpecificApproverID,
-		Level:      1,
-		Status:     domain.ApprovalPending,
+	rule := expense.ApprovalRule
+	if rule == nil || rule.Type != domain.RuleTypeHybrid || rule.Expression == nil {
+		return undecided, false
 	}
 
-	return s.approvalRepo.Create(ctx, approval)
+	return s.evaluateRuleExpression(ctx, expense, rule.Expression, approvals), true
 }
 
-// createHybridApprovals creates approvals for hybrid rule
-func (s *ApprovalService) createHybridApprovals(ctx context.Context, expense *domain.Expense, rule *domain.ApprovalRule) error {
-	// Hybrid combines sequential and percentage
-	// First create sequential approvals
-	if err := s.createSequentialApprovals(ctx, expense, rule); err != nil {
-		return err
+// evaluateRuleExpression decides expr for the expense given its approvals
+func (s *ApprovalService) evaluateRuleExpression(ctx context.Context, expense *domain.Expense, expr *domain.ApprovalExpression, approvals []*domain.Approval) decision {
+	in := newExpressionInput(expense, s.submitterManagerID(ctx, expense), approvals)
+	return evaluateExpression(expr, in)
+}
+
+// maxDelegationHops bounds how far delegations of delegates are followed
+const maxDelegationHops = 5
+
+// resolveDelegation returns the delegation that should receive approverID's
+// approval for the expense, following delegates who are away themselves.
+// The returned delegation carries the final delegate.
+func (s *ApprovalService) resolveDelegation(ctx context.Context, expense *domain.Expense, approverID primitive.ObjectID) *domain.Delegation {
+	if s.delegationRepo == nil {
+		return nil
 	}
 
-	// Then create percentage-based approvals if configured
-	if len(rule.AllowedApprovers) > 0 {
-		return s.createPercentageApprovals(ctx, expense, rule)
+	var resolved *domain.Delegation
+	visited := map[primitive.ObjectID]bool{approverID: true}
+	current := approverID
+	now := time.Now()
+
+	for hop := 0; hop < maxDelegationHops; hop++ {
+		delegations, err := s.delegationRepo.FindActiveByDelegatorID(ctx, current.Hex(), now)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to look up delegations for %s: %v\n", current.Hex(), err)
+			break
+		}
+
+		var next *domain.Delegation
+		for _, delegation := range delegations {
+			// Never hand an approval to the submitter or back around a cycle
+			if delegation.DelegateID == expense.UserID || visited[delegation.DelegateID] {
+				continue
+			}
+			if delegationCovers(delegation, expense) {
+				next = delegation
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		visited[next.DelegateID] = true
+		current = next.DelegateID
+		if resolved == nil {
+			resolved = next
+		} else {
+			// Keep the first delegation for the record but hand over to the last delegate
+			chained := *resolved
+			chained.DelegateID = next.DelegateID
+			resolved = &chained
+		}
 	}
 
-	return nil
+	return resolved
+}
+
+// delegationCovers reports whether the delegation's scope includes the
+// expense. An itemized expense is covered when every line's category is.
+func delegationCovers(delegation *domain.Delegation, expense *domain.Expense) bool {
+	if delegation.MaxAmount != nil && expense.ConvertedAmount > *delegation.MaxAmount {
+		return false
+	}
+	if len(delegation.Categories) == 0 {
+		return true
+	}
+	for category := range categoryAmounts(expense) {
+		if !containsValue(delegation.Categories, category) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReassignPendingApprovals re-routes the delegator's open approvals covered by
+// the delegation to its delegate and returns how many were moved
+func (s *ApprovalService) ReassignPendingApprovals(ctx context.Context, delegation *domain.Delegation) (int, error) {
+	approvals, err := s.approvalRepo.FindOpenByApproverID(ctx, delegation.DelegatorID.Hex())
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch open approvals: %w", err)
+	}
+
+	reassigned := 0
+	for _, approval := range approvals {
+		expense, err := s.expenseRepo.FindByID(ctx, approval.ExpenseID.Hex())
+		if err != nil || expense.Status != domain.StatusPending {
+			continue
+		}
+		if expense.UserID == delegation.DelegateID || !delegationCovers(delegation, expense) {
+			continue
+		}
+
+		// Keep pointing at the original approver if this was already delegated
+		if approval.OnBehalfOfID == nil {
+			delegatorID := delegation.DelegatorID
+			approval.OnBehalfOfID = &delegatorID
+		}
+		delegationID := delegation.ID
+		approval.ApproverID = delegation.DelegateID
+		approval.DelegationID = &delegationID
+		if approval.Status == domain.ApprovalPending {
+			// The delegate gets a full SLA window
+			now := time.Now()
+			approval.AssignedAt = &now
+		}
+
+		err = s.approvalRepo.Transition(ctx, approval, approval.Status)
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			// Decided or moved on meanwhile, leave it as it is
+			continue
+		}
+		if err != nil {
+			return reassigned, fmt.Errorf("failed to reassign approval: %w", err)
+		}
+		reassigned++
+
+		s.invalidateApprovalCaches(expense.CompanyID.Hex(), delegation.DelegatorID.Hex())
+		s.invalidateApprovalCaches(expense.CompanyID.Hex(), delegation.DelegateID.Hex())
+		if approval.Status == domain.ApprovalPending {
+			s.notifyAssigned(ctx, expense, approval)
+		}
+	}
+
+	return reassigned, nil
+}
+
+// effectiveApproverID returns the approver an approval counts for, which is
+// the original approver when a delegate acted on their behalf
+func effectiveApproverID(approval *domain.Approval) primitive.ObjectID {
+	if approval.OnBehalfOfID != nil {
+		return *approval.OnBehalfOfID
+	}
+	return approval.ApproverID
 }
 
 // ApproveExpense approves an expense
@@ -191,48 +553,15 @@ func (s *ApprovalService) ApproveExpense(ctx context.Context, expenseID, approve
 	}
 
 	// Find the approval for this approver
-	var currentApproval *domain.Approval
-	for _, approval := range approvals {
-		if approval.ApproverID.Hex() == approverID && approval.Status == domain.ApprovalPending {
-			currentApproval = approval
-			break
-		}
-	}
-
+	currentApproval := findApproverApproval(approvals, approverID)
 	if currentApproval == nil {
 		return fmt.Errorf("no pending approval found for this user")
 	}
 
-	// Update approval status
-	currentApproval.Status = domain.ApprovalApproved
-	currentApproval.Comments = req.Comments
-	if err := s.approvalRepo.Update(ctx, currentApproval); err != nil {
-		return fmt.Errorf("failed to update approval: %w", err)
-	}
-
-	// Check if expense should be auto-approved based on rules
-	shouldAutoApprove, err := s.checkAutoApproval(ctx, expense, approvals)
-	if err != nil {
-		return fmt.Errorf("failed to check auto-approval: %w", err)
-	}
-
-	if shouldAutoApprove {
-		expense.Status = domain.StatusApproved
-		if err := s.expenseRepo.UpdateStatus(ctx, expenseID, domain.StatusApproved); err != nil {
-			return fmt.Errorf("failed to approve expense: %w", err)
-		}
-	} else {
-		// Increment approval level
-		expense.CurrentApprovalLevel++
-		if err := s.expenseRepo.Update(ctx, expense); err != nil {
-			return fmt.Errorf("failed to update expense: %w", err)
-		}
-	}
-
-	// Invalidate caches
-	s.invalidateApprovalCaches(expense.CompanyID.Hex(), approverID)
-
-	return nil
+	caches := approvalCaches{}
+	err = s.approve(ctx, expense, currentApproval, req, caches)
+	s.flushApprovalCaches(caches)
+	return err
 }
 
 // RejectExpense rejects an expense
@@ -254,167 +583,260 @@ func (s *ApprovalService) RejectExpense(ctx context.Context, expenseID, approver
 	}
 
 	// Find the approval for this approver
-	var currentApproval *domain.Approval
-	for _, approval := range approvals {
-		if approval.ApproverID.Hex() == approverID && approval.Status == domain.ApprovalPending {
-			currentApproval = approval
-			break
-		}
-	}
-
+	currentApproval := findApproverApproval(approvals, approverID)
 	if currentApproval == nil {
 		return fmt.Errorf("no pending approval found for this user")
 	}
 
-	// Update approval status
-	currentApproval.Status = domain.ApprovalRejected
-	currentApproval.Comments = req.Comments
-	if err := s.approvalRepo.Update(ctx, currentApproval); err != nil {
-		return fmt.Errorf("failed to update approval: %w", err)
-	}
+	caches := approvalCaches{}
+	err = s.reject(ctx, expense, currentApproval, req, caches)
+	s.flushApprovalCaches(caches)
+	return err
+}
 
-	// Reject the expense (one rejection rejects all)
-	if err := s.expenseRepo.UpdateStatus(ctx, expenseID, domain.StatusRejected); err != nil {
-		return fmt.Errorf("failed to reject expense: %w", err)
+// ApproveExpenseByApprovalID approves an expense using the approval ID
+func (s *ApprovalService) ApproveExpenseByApprovalID(ctx context.Context, approvalID, approverID string, req *ApprovalActionRequest) error {
+	approval, expense, err := s.loadApprovalForAction(ctx, approvalID, approverID, "approve")
+	if err != nil {
+		return err
 	}
 
-	// Invalidate caches
-	s.invalidateApprovalCaches(expense.CompanyID.Hex(), approverID)
+	caches := approvalCaches{}
+	err = s.approve(ctx, expense, approval, req, caches)
+	s.flushApprovalCaches(caches)
+	return err
+}
 
-	return nil
+// RejectExpenseByApprovalID rejects an expense using the approval ID
+func (s *ApprovalService) RejectExpenseByApprovalID(ctx context.Context, approvalID, approverID string, req *ApprovalActionRequest) error {
+	approval, expense, err := s.loadApprovalForAction(ctx, approvalID, approverID, "reject")
+	if err != nil {
+		return err
+	}
+
+	caches := approvalCaches{}
+	err = s.reject(ctx, expense, approval, req, caches)
+	s.flushApprovalCaches(caches)
+	return err
 }
 
-// ApproveExpenseByApprovalID approves an expense using the approval ID
-func (s *ApprovalService) ApproveExpenseByApprovalID(ctx context.Context, approvalID, approverID string, req *ApprovalActionRequest) error {
+// loadApprovalForAction loads an approval and its expense and verifies that
+// approverID may act on it
+func (s *ApprovalService) loadApprovalForAction(ctx context.Context, approvalID, approverID, action string) (*domain.Approval, *domain.Expense, error) {
 	// Get the approval record
 	approval, err := s.approvalRepo.FindByID(ctx, approvalID)
 	if err != nil {
-		return fmt.Errorf("approval not found")
+		return nil, nil, fmt.Errorf("approval not found")
 	}
 
 	// Verify that the approver is the one assigned to this approval
 	if approval.ApproverID.Hex() != approverID {
-		return fmt.Errorf("you are not authorized to approve this expense")
+		return nil, nil, &actionError{outcome: BulkForbidden, message: fmt.Sprintf("you are not authorized to %s this expense", action)}
 	}
 
 	// Check if already processed
-	if approval.Status != domain.ApprovalPending {
-		return fmt.Errorf("approval is already %s", approval.Status)
+	if approval.Status != domain.ApprovalPending && approval.Status != domain.ApprovalQueued {
+		return nil, nil, &actionError{outcome: BulkSkipped, message: fmt.Sprintf("approval is already %s", approval.Status)}
 	}
 
 	// Get the expense
-	expenseID := approval.ExpenseID.Hex()
-	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	expense, err := s.expenseRepo.FindByID(ctx, approval.ExpenseID.Hex())
 	if err != nil {
-		return fmt.Errorf("expense not found")
+		return nil, nil, fmt.Errorf("expense not found")
 	}
 
 	if expense.Status != domain.StatusPending {
-		return fmt.Errorf("expense is already %s", expense.Status)
+		return nil, nil, &actionError{outcome: BulkSkipped, message: fmt.Sprintf("expense is already %s", expense.Status)}
 	}
 
-	// Update approval status
-	approval.Status = domain.ApprovalApproved
-	approval.Comments = req.Comments
-	if err := s.approvalRepo.Update(ctx, approval); err != nil {
-		return fmt.Errorf("failed to update approval: %w", err)
+	return approval, expense, nil
+}
+
+// findApproverApproval returns the approver's pending approval, falling back
+// to a queued one so that callers can report that the level is not reached
+func findApproverApproval(approvals []*domain.Approval, approverID string) *domain.Approval {
+	var queued *domain.Approval
+	for _, approval := range approvals {
+		if approval.ApproverID.Hex() != approverID {
+			continue
+		}
+		if approval.Status == domain.ApprovalPending {
+			return approval
+		}
+		if approval.Status == domain.ApprovalQueued && queued == nil {
+			queued = approval
+		}
 	}
+	return queued
+}
 
-	// Get all approvals for this expense to check if we should auto-approve
-	approvals, err := s.approvalRepo.FindByExpenseID(ctx, expenseID)
-	if err != nil {
-		return fmt.Errorf("failed to fetch approvals: %w", err)
+// ensureLevelReached refuses actions on sequential levels that are still queued
+func ensureLevelReached(expense *domain.Expense, approval *domain.Approval) error {
+	if approval.Status == domain.ApprovalQueued {
+		return fmt.Errorf("approval level %d has not been reached yet; expense is awaiting level %d",
+			approval.Level, expense.CurrentApprovalLevel+1)
 	}
+	return nil
+}
 
-	// Check if expense should be auto-approved based on rules
-	shouldAutoApprove, err := s.checkAutoApproval(ctx, expense, approvals)
-	if err != nil {
-		return fmt.Errorf("failed to check auto-approval: %w", err)
+// approve records the approval and advances or finalizes the expense
+func (s *ApprovalService) approve(ctx context.Context, expense *domain.Expense, approval *domain.Approval, req *ApprovalActionRequest, caches approvalCaches) error {
+	if approval.ApproverID == expense.UserID {
+		return &actionError{outcome: BulkForbidden, message: "you cannot approve your own expense"}
 	}
 
-	if shouldAutoApprove {
-		expense.Status = domain.StatusApproved
-		if err := s.expenseRepo.UpdateStatus(ctx, expenseID, domain.StatusApproved); err != nil {
-			return fmt.Errorf("failed to approve expense: %w", err)
+	if err := ensureLevelReached(expense, approval); err != nil {
+		return err
+	}
+
+	// Approvers cannot finalize above their limit, the approval moves up
+	// instead. Intermediate levels approve normally.
+	approver, err := s.userRepo.FindByID(ctx, approval.ApproverID.Hex())
+	if err == nil && !withinApprovalLimit(approver, expense.ConvertedAmount) {
+		finalizes, err := s.wouldFinalize(ctx, expense, approval)
+		if err != nil {
+			return err
 		}
-	} else {
-		// Increment approval level
-		expense.CurrentApprovalLevel++
-		if err := s.expenseRepo.Update(ctx, expense); err != nil {
-			return fmt.Errorf("failed to update expense: %w", err)
+		if finalizes {
+			return s.escalateOverLimit(ctx, expense, approval, approver, req, caches)
 		}
 	}
 
-	// Invalidate caches
-	s.invalidateApprovalCaches(expense.CompanyID.Hex(), approverID)
+	// Claim the approval, only one decision can win it
+	before := auditSnapshot(approval)
+	now := time.Now()
+	approval.Status = domain.ApprovalApproved
+	approval.Comments = req.Comments
+	approval.ApprovedAt = &now
+	if err := s.claimApproval(ctx, approval, domain.ApprovalPending); err != nil {
+		return err
+	}
+	s.recordApproval(ctx, expense, approval, domain.AuditApprovalApproved, before)
+
+	caches.add(expense.CompanyID.Hex(), approval.ApproverID.Hex())
 
-	return nil
+	return s.advanceExpense(ctx, expense, approval, caches)
 }
 
-// RejectExpenseByApprovalID rejects an expense using the approval ID
-func (s *ApprovalService) RejectExpenseByApprovalID(ctx context.Context, approvalID, approverID string, req *ApprovalActionRequest) error {
-	// Get the approval record
-	approval, err := s.approvalRepo.FindByID(ctx, approvalID)
-	if err != nil {
-		return fmt.Errorf("approval not found")
+// reject records the rejection and rejects the expense
+func (s *ApprovalService) reject(ctx context.Context, expense *domain.Expense, approval *domain.Approval, req *ApprovalActionRequest, caches approvalCaches) error {
+	if err := ensureLevelReached(expense, approval); err != nil {
+		return err
 	}
 
-	// Verify that the approver is the one assigned to this approval
-	if approval.ApproverID.Hex() != approverID {
-		return fmt.Errorf("you are not authorized to reject this expense")
+	// Claim the approval, only one decision can win it
+	before := auditSnapshot(approval)
+	now := time.Now()
+	approval.Status = domain.ApprovalRejected
+	approval.Comments = req.Comments
+	approval.ApprovedAt = &now
+	if err := s.claimApproval(ctx, approval, domain.ApprovalPending); err != nil {
+		return err
 	}
+	s.recordApproval(ctx, expense, approval, domain.AuditApprovalRejected, before)
 
-	// Check if already processed
-	if approval.Status != domain.ApprovalPending {
-		return fmt.Errorf("approval is already %s", approval.Status)
-	}
+	caches.add(expense.CompanyID.Hex(), approval.ApproverID.Hex())
 
-	// Get the expense
-	expenseID := approval.ExpenseID.Hex()
-	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	// Expression rules stay open while another branch can still approve
+	approvals, err := s.approvalRepo.FindByExpenseID(ctx, expense.ID.Hex())
 	if err != nil {
-		return fmt.Errorf("expense not found")
+		return fmt.Errorf("failed to fetch approvals: %w", err)
+	}
+	if result, ok := s.expressionDecision(ctx, expense, currentApprovals(expense, approvals)); ok && result != decidedFalse {
+		return nil
 	}
 
-	if expense.Status != domain.StatusPending {
-		return fmt.Errorf("expense is already %s", expense.Status)
+	// Reject the expense (one rejection rejects all)
+	err = s.transitionExpense(ctx, expense, domain.StatusPending, domain.StatusRejected, domain.AuditExpenseDecided)
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return s.concurrentDecisionError(ctx, expense, domain.StatusRejected)
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		return expenseDeletedError()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reject expense: %w", err)
 	}
 
-	// Update approval status
-	approval.Status = domain.ApprovalRejected
-	approval.Comments = req.Comments
-	if err := s.approvalRepo.Update(ctx, approval); err != nil {
-		return fmt.Errorf("failed to update approval: %w", err)
+	return nil
+}
+
+// promoteNextLevel moves the queued approvals of the level after level to
+// pending once every approval of level is approved
+func (s *ApprovalService) promoteNextLevel(ctx context.Context, approvals []*domain.Approval, level int) ([]*domain.Approval, error) {
+	var next []*domain.Approval
+	for _, approval := range approvals {
+		if approval.Level == level && approval.Status != domain.ApprovalApproved {
+			return nil, nil
+		}
+		if approval.Level == level+1 && approval.Status == domain.ApprovalQueued {
+			next = append(next, approval)
+		}
 	}
 
-	// Reject the expense (one rejection rejects all)
-	if err := s.expenseRepo.UpdateStatus(ctx, expenseID, domain.StatusRejected); err != nil {
-		return fmt.Errorf("failed to reject expense: %w", err)
+	now := time.Now()
+	promoted := make([]*domain.Approval, 0, len(next))
+	for _, approval := range next {
+		approval.Status = domain.ApprovalPending
+		approval.AssignedAt = &now
+		err := s.approvalRepo.Transition(ctx, approval, domain.ApprovalQueued)
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			// Promoted by a concurrent approval, or cancelled by a re-routing
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to activate next approval level: %w", err)
+		}
+		promoted = append(promoted, approval)
 	}
 
-	// Invalidate caches
-	s.invalidateApprovalCaches(expense.CompanyID.Hex(), approverID)
+	return promoted, nil
+}
 
-	return nil
+// currentApprovals keeps the approvals of the expense's current round that
+// still count. Escalated approvals are replaced by their escalation, change
+// requests by the approval created on resubmission, and cancelled ones
+// belong to a superseded routing.
+func currentApprovals(expense *domain.Expense, approvals []*domain.Approval) []*domain.Approval {
+	current := make([]*domain.Approval, 0, len(approvals))
+	for _, approval := range approvals {
+		if approval.Round != expense.ApprovalRound {
+			continue
+		}
+		switch approval.Status {
+		case domain.ApprovalEscalated, domain.ApprovalCancelled, domain.ApprovalChangesRequested:
+			continue
+		}
+		current = append(current, approval)
+	}
+	return current
 }
 
 // checkAutoApproval checks if expense should be auto-approved based on rules
 func (s *ApprovalService) checkAutoApproval(ctx context.Context, expense *domain.Expense, approvals []*domain.Approval) (bool, error) {
-	// Get approval rule
-	rule, err := s.approvalRuleRepo.FindByCompanyID(ctx, expense.CompanyID.Hex())
-	if err != nil {
+	// Expenses routed through an amount band need every required approver
+	if expense.ApprovalThreshold != nil {
+		return s.checkAllApprovalsComplete(approvals), nil
+	}
+
+	// Decide by the rule the expense was routed under
+	rule := expense.ApprovalRule
+	if rule == nil {
 		// No rule, check if all approvals are complete
 		return s.checkAllApprovalsComplete(approvals), nil
 	}
 
 	switch rule.Type {
-	case domain.RuleTypeSequential:
+	case domain.RuleTypeSequential, domain.RuleTypeManagerChain:
 		return s.checkSequentialApproval(approvals), nil
 	case domain.RuleTypePercentage:
 		return s.checkPercentageApproval(approvals, rule), nil
 	case domain.RuleTypeSpecificApprover:
 		return s.checkSpecificApproverApproval(approvals, rule), nil
 	case domain.RuleTypeHybrid:
+		if rule.Expression != nil {
+			return s.evaluateRuleExpression(ctx, expense, rule.Expression, approvals) == decidedTrue, nil
+		}
 		return s.checkHybridApproval(approvals, rule), nil
 	default:
 		return s.checkAllApprovalsComplete(approvals), nil
@@ -450,7 +872,7 @@ func (s *ApprovalService) checkSpecificApproverApproval(approvals []*domain.Appr
 	}
 
 	for _, approval := range approvals {
-		if approval.ApproverID == *rule.SpecificApproverID && approval.Status == domain.ApprovalApproved {
+		if effectiveApproverID(approval) == *rule.SpecificApproverID && approval.Status == domain.ApprovalApproved {
 			return true
 		}
 	}
@@ -544,6 +966,45 @@ func (s *ApprovalService) GetPendingApprovalsWithDetails(ctx context.Context, ap
 	return approvals, nil
 }
 
+// pendingCursorScope keeps cursors of other lists out of pending approvals
+const pendingCursorScope = "approvals:pending"
+
+// GetPendingApprovalsAfter retrieves the approver's pending approvals after
+// the cursor, newest first, and the cursor of the next page, empty on the
+// last page. An empty cursor starts at the newest.
+func (s *ApprovalService) GetPendingApprovalsAfter(ctx context.Context, approverID, cursorToken string, limit int) ([]*domain.ApprovalWithDetails, string, error) {
+	var after *domain.PageCursor
+	if cursorToken != "" {
+		position, err := cursor.Decode(cursorToken, pendingCursorScope, s.cfg)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid cursor")
+		}
+		id, err := primitive.ObjectIDFromHex(position.ID)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid cursor")
+		}
+		after = &domain.PageCursor{CreatedAt: position.CreatedAt, ID: id}
+	}
+
+	// One more than the page tells whether another page follows
+	approvals, err := s.approvalRepo.FindPendingByApproverIDAfter(ctx, approverID, after, limit+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch pending approvals: %w", err)
+	}
+
+	if len(approvals) <= limit {
+		return approvals, "", nil
+	}
+	approvals = approvals[:limit]
+
+	last := approvals[limit-1]
+	next, err := cursor.Encode(&cursor.Cursor{Scope: pendingCursorScope, CreatedAt: last.CreatedAt, ID: last.ID.Hex()}, s.cfg)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to issue cursor: %w", err)
+	}
+	return approvals, next, nil
+}
+
 // GetApprovalHistory retrieves approval history for an expense
 func (s *ApprovalService) GetApprovalHistory(ctx context.Context, expenseID string) ([]*domain.Approval, error) {
 	approvals, err := s.approvalRepo.FindByExpenseID(ctx, expenseID)
@@ -553,6 +1014,143 @@ func (s *ApprovalService) GetApprovalHistory(ctx context.Context, expenseID stri
 	return approvals, nil
 }
 
+type RerouteRequest struct {
+	ExpenseIDs []string `json:"expense_ids,omitempty"` // Empty re-routes every pending expense of the company
+}
+
+type RerouteResult struct {
+	Rerouted int              `json:"rerouted"`
+	Failed   []RerouteFailure `json:"failed,omitempty"`
+}
+
+type RerouteFailure struct {
+	ExpenseID string `json:"expense_id"`
+	Error     string `json:"error"`
+}
+
+// RerouteExpenses re-routes pending expenses under the company's current
+// approval rule (Admin only). Open approvals are cancelled and a new approval
+// round starts; decisions already taken stay in the history.
+func (s *ApprovalService) RerouteExpenses(ctx context.Context, companyID string, req *RerouteRequest) (*RerouteResult, error) {
+	var expenses []*domain.Expense
+	if len(req.ExpenseIDs) == 0 {
+		pending, err := s.expenseRepo.FindPendingByCompanyID(ctx, companyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pending expenses: %w", err)
+		}
+		// Pending reports are re-routed like expenses
+		reports, err := s.expenseRepo.FindPendingRoutingByCompanyID(ctx, companyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pending expense reports: %w", err)
+		}
+		expenses = append(pending, reports...)
+	}
+
+	result := &RerouteResult{}
+	for _, expenseID := range req.ExpenseIDs {
+		expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+		if err != nil || expense.CompanyID.Hex() != companyID {
+			result.Failed = append(result.Failed, RerouteFailure{ExpenseID: expenseID, Error: "expense not found"})
+			continue
+		}
+		expenses = append(expenses, expense)
+	}
+
+	for _, expense := range expenses {
+		if err := s.rerouteExpense(ctx, expense); err != nil {
+			result.Failed = append(result.Failed, RerouteFailure{ExpenseID: expense.ID.Hex(), Error: err.Error()})
+			continue
+		}
+		result.Rerouted++
+	}
+
+	return result, nil
+}
+
+// rerouteExpense starts a new approval round under the company's current rule
+func (s *ApprovalService) rerouteExpense(ctx context.Context, expense *domain.Expense) error {
+	if expense.Status != domain.StatusPending {
+		return fmt.Errorf("expense is already %s", expense.Status)
+	}
+	if expense.ReportID != nil {
+		return inReport(expense)
+	}
+
+	rule, err := s.matchingRule(ctx, expense)
+	if err != nil {
+		return err
+	}
+
+	plan, err := s.planApprovals(ctx, expense, rule)
+	if err != nil {
+		return fmt.Errorf("failed to plan approvals: %w", err)
+	}
+
+	fmt.Printf("🔀 Re-routing expense %s\n", expense.ID.Hex())
+
+	return s.startApprovalRound(ctx, expense, plan)
+}
+
+// startApprovalRound cancels the expense's open approvals and applies plan in
+// a new approval round. Decisions already taken stay in the history.
+func (s *ApprovalService) startApprovalRound(ctx context.Context, expense *domain.Expense, plan *ApprovalPlan) error {
+	approvals, err := s.approvalRepo.FindByExpenseID(ctx, expense.ID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to fetch approvals: %w", err)
+	}
+
+	// Move the expense to the new round first, so that a concurrent decision
+	// either wins before it or finds its approval superseded
+	expense.ApprovalRound++
+	expense.CurrentApprovalLevel = 0
+	expense.ApprovalThreshold = nil
+	expense.ApprovalRuleID = nil
+	expense.ApprovalRule = nil
+	if err := s.expenseRepo.StartApprovalRound(ctx, expense); err != nil {
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			return fmt.Errorf("expense was modified by a concurrent request, reload it and try again")
+		}
+		return err
+	}
+
+	if err := s.approvalRepo.CancelOpenByExpenseID(ctx, expense.ID.Hex()); err != nil {
+		return err
+	}
+
+	companyID := expense.CompanyID.Hex()
+	for _, approval := range approvals {
+		if approval.Status == domain.ApprovalPending || approval.Status == domain.ApprovalQueued {
+			s.invalidateApprovalCaches(companyID, approval.ApproverID.Hex())
+		}
+	}
+
+	fmt.Printf("🔁 Starting approval round %d for expense %s\n", expense.ApprovalRound, expense.ID.Hex())
+
+	if err := s.applyPlan(ctx, expense, plan); err != nil {
+		return fmt.Errorf("failed to initialize approvals: %w", err)
+	}
+
+	_ = cache.DeletePattern(fmt.Sprintf("expenses:user:%s:*", expense.UserID.Hex()))
+
+	return nil
+}
+
+// approvalCaches collects the approvers whose caches an action touched, keyed
+// by approver ID, so that a batch of actions invalidates each approver once
+type approvalCaches map[string]string
+
+// add records the approver of the company for invalidation
+func (c approvalCaches) add(companyID, approverID string) {
+	c[approverID] = companyID
+}
+
+// flushApprovalCaches invalidates the caches of every collected approver
+func (s *ApprovalService) flushApprovalCaches(caches approvalCaches) {
+	for approverID, companyID := range caches {
+		s.invalidateApprovalCaches(companyID, approverID)
+	}
+}
+
 // invalidateApprovalCaches invalidates approval-related caches
 func (s *ApprovalService) invalidateApprovalCaches(companyID, approverID string) {
 	// Invalidate pending approvals cache (old format)
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/currency"
+	"expensio-backend/pkg/validator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SimulationRequest struct {
+	SubmitterID string                   `json:"submitter_id"`
+	Amount      float64                  `json:"amount"`
+	Currency    string                   `json:"currency"`
+	Category    string                   `json:"category"`
+	LineItems   []domain.ExpenseLineItem `json:"line_items,omitempty"` // Checked per line like an itemized expense
+	RuleID      string                   `json:"rule_id,omitempty"`    // Defaults to the rule matching the expense
+}
+
+// SimulateRoute returns how a hypothetical expense would be routed, through
+// the same planning code as InitializeApprovals, without creating anything
+func (s *ApprovalService) SimulateRoute(ctx context.Context, companyID string, req *SimulationRequest) (*ApprovalPlan, error) {
+	companyObjID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID")
+	}
+
+	submitterID, err := primitive.ObjectIDFromHex(req.SubmitterID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid submitter_id")
+	}
+	submitter, err := s.userRepo.FindByID(ctx, req.SubmitterID)
+	if err != nil || submitter.CompanyID != companyObjID {
+		return nil, fmt.Errorf("submitter not found")
+	}
+
+	if err := validator.ValidateAmount(req.Amount); err != nil {
+		return nil, err
+	}
+	if err := validator.ValidateCurrency(req.Currency); err != nil {
+		return nil, err
+	}
+	if req.Category == "" && len(req.LineItems) > 0 {
+		req.Category = string(primaryCategory(req.LineItems))
+	}
+	if err := validator.ValidateCategory(req.Category); err != nil {
+		return nil, err
+	}
+	if err := validateLineItems(req.LineItems, req.Amount, req.Currency); err != nil {
+		return nil, err
+	}
+
+	company, err := s.companyRepo.FindByID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found")
+	}
+
+	convertedAmount, exchangeRate, err := currency.ConvertCurrency(req.Amount, req.Currency, company.BaseCurrency, s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert currency: %w", err)
+	}
+
+	expense := &domain.Expense{
+		UserID:          submitterID,
+		CompanyID:       companyObjID,
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		ConvertedAmount: convertedAmount,
+		ExchangeRate:    exchangeRate,
+		Category:        domain.ExpenseCategory(req.Category),
+		LineItems:       req.LineItems,
+		Status:          domain.StatusPending,
+	}
+	convertLineItems(expense)
+
+	rule, err := s.simulatedRule(ctx, expense, submitter, req.RuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.planApprovals(ctx, expense, rule)
+}
+
+// simulatedRule returns the requested rule of the company, which may still be
+// inactive, or the rule matching the expense when none is requested
+func (s *ApprovalService) simulatedRule(ctx context.Context, expense *domain.Expense, submitter *domain.User, ruleID string) (*domain.ApprovalRule, error) {
+	if ruleID == "" {
+		// Nil means the default manager approval applies
+		return s.selectRule(ctx, expense, submitter)
+	}
+
+	rule, err := s.approvalRuleRepo.FindByID(ctx, ruleID)
+	if err != nil || rule.CompanyID != expense.CompanyID {
+		return nil, fmt.Errorf("approval rule not found")
+	}
+	return rule, nil
+}
+
+// describeAll describes a decision that needs every approver
+func describeAll(count int, gated bool) string {
+	if gated {
+		return fmt.Sprintf("all %d levels approve in order", count)
+	}
+	return fmt.Sprintf("all %d approvers approve", count)
+}
+
+// describePercentage describes the decision of a percentage rule
+func describePercentage(rule *domain.ApprovalRule) string {
+	return fmt.Sprintf("at least %g%% of %d approvers approve", *rule.PercentageRequired, len(rule.AllowedApprovers))
+}
+
+// describeHybrid describes the decision of a hybrid rule without expression
+func describeHybrid(rule *domain.ApprovalRule) string {
+	description := ""
+	if rule.SpecificApproverID != nil {
+		description = fmt.Sprintf("approver %s approves, or ", rule.SpecificApproverID.Hex())
+	}
+	if rule.PercentageRequired != nil {
+		description += fmt.Sprintf("at least %g%% of all approvers approve, or ", *rule.PercentageRequired)
+	}
+	return description + "all approvers approve"
+}
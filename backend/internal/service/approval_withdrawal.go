@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"expensio-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WithdrawExpense takes an expense that is pending or awaiting changes out of
+// approval on behalf of its submitter
+func (s *ApprovalService) WithdrawExpense(ctx context.Context, expense *domain.Expense) error {
+	if err := s.claimWithdrawal(ctx, expense); err != nil {
+		return err
+	}
+
+	fmt.Printf("↩️  Expense %s withdrawn by its submitter\n", expense.ID.Hex())
+
+	return s.closeApprovals(ctx, expense, "withdrawn")
+}
+
+// DeleteExpenseApprovals closes the approvals of an expense that is being
+// deleted like a withdrawal would, then deletes them
+func (s *ApprovalService) DeleteExpenseApprovals(ctx context.Context, expense *domain.Expense) error {
+	// Withdraw first so that no decision lands on the expense while it goes
+	if expense.Status != domain.StatusWithdrawn {
+		if err := s.claimWithdrawal(ctx, expense); err != nil {
+			return err
+		}
+	}
+
+	if err := s.closeApprovals(ctx, expense, "deleted"); err != nil {
+		return err
+	}
+
+	return s.approvalRepo.DeleteByExpenseID(ctx, expense.ID.Hex())
+}
+
+// claimWithdrawal moves the expense to withdrawn unless a decision won first
+func (s *ApprovalService) claimWithdrawal(ctx context.Context, expense *domain.Expense) error {
+	from := expense.Status
+	if from != domain.StatusPending && from != domain.StatusChangesRequested {
+		return fmt.Errorf("cannot withdraw expense that is already %s", from)
+	}
+
+	err := s.transitionExpense(ctx, expense, from, domain.StatusWithdrawn, domain.AuditExpenseWithdrawn)
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return fmt.Errorf("expense was decided by a concurrent request, reload it to see its state")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to withdraw expense: %w", err)
+	}
+
+	return nil
+}
+
+// closeApprovals cancels the expense's open approvals, invalidates the caches
+// of their approvers and notifies every approver who already acted on it
+func (s *ApprovalService) closeApprovals(ctx context.Context, expense *domain.Expense, action string) error {
+	approvals, err := s.approvalRepo.FindByExpenseID(ctx, expense.ID.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to fetch approvals: %w", err)
+	}
+
+	if err := s.approvalRepo.CancelOpenByExpenseID(ctx, expense.ID.Hex()); err != nil {
+		return err
+	}
+
+	companyID := expense.CompanyID.Hex()
+	notified := make(map[primitive.ObjectID]bool)
+	for _, approval := range approvals {
+		switch {
+		case approval.Status == domain.ApprovalPending || approval.Status == domain.ApprovalQueued:
+			s.invalidateApprovalCaches(companyID, approval.ApproverID.Hex())
+		case approval.ApprovedAt != nil && !notified[approval.ApproverID]:
+			notified[approval.ApproverID] = true
+			s.notifyApprover(approval.ApproverID, expense, action)
+		}
+	}
+
+	return nil
+}
+
+// notifyApprover tells an approver who acted on the expense that its
+// submitter took it out of approval
+func (s *ApprovalService) notifyApprover(approverID primitive.ObjectID, expense *domain.Expense, action string) {
+	fmt.Printf("📨 Notified approver %s: expense %s was %s by its submitter\n",
+		approverID.Hex(), expense.ID.Hex(), action)
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxAuditAppendAttempts bounds how often an entry retries when concurrent
+// entries of the same company race for the next sequence
+const maxAuditAppendAttempts = 5
+
+// Audited entity types
+const (
+	auditEntityExpense  = "expense"
+	auditEntityApproval = "approval"
+	auditEntityRule     = "approval_rule"
+	auditEntityUser     = "user"
+	auditEntityBatch    = "reimbursement_batch"
+	auditEntityReport   = "expense_report"
+)
+
+type AuditService struct {
+	auditRepo domain.AuditRepository
+	cfg       *config.Config
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditRepo domain.AuditRepository, cfg *config.Config) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		cfg:       cfg,
+	}
+}
+
+// AuditVerification is the result of checking a company's audit chain
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Entries  int    `json:"entries"`
+	HeadHash string `json:"head_hash,omitempty"` // Keep it to detect later removal of the newest entries
+	BrokenAt int64  `json:"broken_at,omitempty"` // Sequence of the first entry that does not verify
+	Reason   string `json:"reason,omitempty"`
+}
+
+// auditActorKey carries the actor of a change made without a session, e.g.
+// through an action link
+type auditActorKey struct{}
+
+// withAuditActor attributes the changes made with ctx to actorID
+func withAuditActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actorID)
+}
+
+// asSystem attributes the changes made with ctx to the system, e.g. decisions
+// the routing takes on its own
+func asSystem(ctx context.Context) context.Context {
+	return withAuditActor(ctx, "")
+}
+
+// Record appends an entry for the change to the company's chain. The change
+// already happened, so a failure to record it is logged rather than returned.
+func (s *AuditService) Record(ctx context.Context, companyID primitive.ObjectID, action domain.AuditAction, entityType string, entityID primitive.ObjectID, before, after interface{}) {
+	entry := &domain.AuditEntry{
+		CompanyID:  companyID,
+		ActorID:    auditActor(ctx),
+		IP:         auditIP(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     auditSnapshot(before),
+		After:      auditSnapshot(after),
+	}
+
+	for attempt := 0; attempt < maxAuditAppendAttempts; attempt++ {
+		last, err := s.auditRepo.FindLastByCompanyID(ctx, companyID.Hex())
+		if err != nil {
+			break
+		}
+
+		entry.Sequence = 1
+		entry.PrevHash = ""
+		if last != nil {
+			entry.Sequence = last.Sequence + 1
+			entry.PrevHash = last.Hash
+		}
+		// Mongo keeps milliseconds, the hash must survive the round trip
+		entry.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+		entry.Hash = hashAuditEntry(entry)
+
+		err = s.auditRepo.Append(ctx, entry)
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			continue
+		}
+		if err == nil {
+			return
+		}
+		break
+	}
+
+	fmt.Printf("⚠️  Failed to record audit entry %s for %s %s\n", action, entityType, entityID.Hex())
+}
+
+// GetEntityTrail returns the audit entries about one entity of the company
+func (s *AuditService) GetEntityTrail(ctx context.Context, companyID, entityID string) ([]*domain.AuditEntry, error) {
+	entries, err := s.auditRepo.FindByEntityID(ctx, companyID, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch audit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// VerifyChain recomputes every hash of the company's chain and checks that
+// each entry links to the one before it without gaps
+func (s *AuditService) VerifyChain(ctx context.Context, companyID string) (*AuditVerification, error) {
+	entries, err := s.auditRepo.FindByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch audit entries: %w", err)
+	}
+
+	result := &AuditVerification{Valid: true, Entries: len(entries)}
+	prevHash := ""
+	for i, entry := range entries {
+		reason := ""
+		switch {
+		case entry.Sequence != int64(i+1):
+			reason = fmt.Sprintf("expected sequence %d, found %d", i+1, entry.Sequence)
+		case entry.PrevHash != prevHash:
+			reason = "link to the previous entry does not match"
+		case entry.Hash != hashAuditEntry(entry):
+			reason = "entry content does not match its hash"
+		}
+		if reason != "" {
+			result.Valid = false
+			result.BrokenAt = int64(i + 1)
+			result.Reason = reason
+			return result, nil
+		}
+		prevHash = entry.Hash
+	}
+
+	result.HeadHash = prevHash
+	return result, nil
+}
+
+// hashAuditEntry hashes everything recorded about the change together with
+// the previous entry's hash
+func hashAuditEntry(entry *domain.AuditEntry) string {
+	actorID := ""
+	if entry.ActorID != nil {
+		actorID = entry.ActorID.Hex()
+	}
+
+	fields := []string{
+		entry.CompanyID.Hex(),
+		strconv.FormatInt(entry.Sequence, 10),
+		actorID,
+		entry.IP,
+		string(entry.Action),
+		entry.EntityType,
+		entry.EntityID.Hex(),
+		string(entry.Before),
+		string(entry.After),
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		entry.PrevHash,
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// auditSnapshot serializes an entity as it is at the time of the change
+func auditSnapshot(value interface{}) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	if snapshot, ok := value.(json.RawMessage); ok {
+		// Taken before the entity was changed
+		return snapshot
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// auditActor returns the user making the change, nil for the system
+func auditActor(ctx context.Context) *primitive.ObjectID {
+	actorID, ok := ctx.Value(auditActorKey{}).(string)
+	if !ok {
+		// Set by the auth middleware, the request context exposes its locals
+		actorID, ok = ctx.Value("userID").(string)
+	}
+	if !ok {
+		return nil
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(actorID)
+	if err != nil {
+		return nil
+	}
+	return &objectID
+}
+
+// auditIP returns the client IP of the request making the change
+func auditIP(ctx context.Context) string {
+	ip, _ := ctx.Value("clientIP").(string)
+	return ip
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/domain"
+)
+
+type CompanyService struct {
+	companyRepo domain.CompanyRepository
+	userRepo    domain.UserRepository
+	cfg         *config.Config
+}
+
+// NewCompanyService creates a new company service
+func NewCompanyService(companyRepo domain.CompanyRepository, userRepo domain.UserRepository, cfg *config.Config) *CompanyService {
+	return &CompanyService{
+		companyRepo: companyRepo,
+		userRepo:    userRepo,
+		cfg:         cfg,
+	}
+}
+
+type CompanySettingsRequest struct {
+	ApprovalSLAHours   *int                       `json:"approval_sla_hours,omitempty"`
+	SelfApprovalPolicy *domain.SelfApprovalPolicy `json:"self_approval_policy,omitempty"`
+	NoApproverPolicy   *domain.NoApproverPolicy   `json:"no_approver_policy,omitempty"`
+	FallbackApproverID *string                    `json:"fallback_approver_id,omitempty"` // Empty string clears it
+}
+
+// GetSettings retrieves the workflow settings of the company
+func (s *CompanyService) GetSettings(ctx context.Context, companyID string) (*domain.CompanySettings, error) {
+	company, err := s.companyRepo.FindByID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found")
+	}
+	settings := withDefaultSettings(company.Settings)
+	return &settings, nil
+}
+
+// UpdateSettings updates the workflow settings of the company (Admin only)
+func (s *CompanyService) UpdateSettings(ctx context.Context, companyID string, req *CompanySettingsRequest) (*domain.CompanySettings, error) {
+	company, err := s.companyRepo.FindByID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("company not found")
+	}
+
+	if req.ApprovalSLAHours != nil {
+		if *req.ApprovalSLAHours < 0 {
+			return nil, fmt.Errorf("approval_sla_hours cannot be negative")
+		}
+		company.Settings.ApprovalSLAHours = *req.ApprovalSLAHours
+	}
+
+	if req.SelfApprovalPolicy != nil {
+		switch *req.SelfApprovalPolicy {
+		case domain.SelfApprovalSkip, domain.SelfApprovalSubstitute:
+			company.Settings.SelfApprovalPolicy = *req.SelfApprovalPolicy
+		default:
+			return nil, fmt.Errorf("self_approval_policy must be %q or %q", domain.SelfApprovalSkip, domain.SelfApprovalSubstitute)
+		}
+	}
+
+	if req.NoApproverPolicy != nil {
+		switch *req.NoApproverPolicy {
+		case domain.NoApproverAutoApprove, domain.NoApproverFallback, domain.NoApproverReject:
+			company.Settings.NoApproverPolicy = *req.NoApproverPolicy
+		default:
+			return nil, fmt.Errorf("no_approver_policy must be %q, %q or %q",
+				domain.NoApproverAutoApprove, domain.NoApproverFallback, domain.NoApproverReject)
+		}
+	}
+
+	if req.FallbackApproverID != nil {
+		if *req.FallbackApproverID == "" {
+			company.Settings.FallbackApproverID = nil
+		} else {
+			fallback, err := s.userRepo.FindByID(ctx, *req.FallbackApproverID)
+			if err != nil || fallback.CompanyID != company.ID || !fallback.IsActive {
+				return nil, fmt.Errorf("fallback approver not found")
+			}
+			if fallback.Role == domain.RoleEmployee {
+				return nil, fmt.Errorf("fallback approver must be a manager or admin")
+			}
+			company.Settings.FallbackApproverID = &fallback.ID
+		}
+	}
+
+	if company.Settings.NoApproverPolicy == domain.NoApproverFallback && company.Settings.FallbackApproverID == nil {
+		return nil, fmt.Errorf("fallback_approver_id is required with the %q policy", domain.NoApproverFallback)
+	}
+
+	if err := s.companyRepo.Update(ctx, company); err != nil {
+		return nil, fmt.Errorf("failed to update company settings: %w", err)
+	}
+
+	settings := withDefaultSettings(company.Settings)
+	return &settings, nil
+}
+
+// withDefaultSettings fills in the policies a company has not chosen yet
+func withDefaultSettings(settings domain.CompanySettings) domain.CompanySettings {
+	if settings.SelfApprovalPolicy == "" {
+		settings.SelfApprovalPolicy = domain.SelfApprovalSkip
+	}
+	if settings.NoApproverPolicy == "" {
+		settings.NoApproverPolicy = domain.NoApproverAutoApprove
+	}
+	return settings
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/validator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DelegationService struct {
+	delegationRepo  domain.DelegationRepository
+	userRepo        domain.UserRepository
+	approvalService *ApprovalService
+	cfg             *config.Config
+}
+
+// NewDelegationService creates a new delegation service
+func NewDelegationService(
+	delegationRepo domain.DelegationRepository,
+	userRepo domain.UserRepository,
+	approvalService *ApprovalService,
+	cfg *config.Config,
+) *DelegationService {
+	return &DelegationService{
+		delegationRepo:  delegationRepo,
+		userRepo:        userRepo,
+		approvalService: approvalService,
+		cfg:             cfg,
+	}
+}
+
+type DelegationRequest struct {
+	DelegatorID     *string   `json:"delegator_id,omitempty"` // Admin only, defaults to the caller
+	DelegateID      string    `json:"delegate_id"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	Categories      []string  `json:"categories,omitempty"`
+	MaxAmount       *float64  `json:"max_amount,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+	ReassignPending bool      `json:"reassign_pending"` // Move the delegator's open approvals to the delegate
+}
+
+type DelegationResponse struct {
+	Delegation *domain.Delegation `json:"delegation"`
+	Reassigned int                `json:"reassigned"`
+}
+
+// CreateDelegation creates a delegation for the caller, or for any user of the company when called by an admin
+func (s *DelegationService) CreateDelegation(ctx context.Context, companyID, userID, role string, req *DelegationRequest) (*DelegationResponse, error) {
+	companyObjID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID")
+	}
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	delegatorID := userObjID
+	if req.DelegatorID != nil && *req.DelegatorID != "" && *req.DelegatorID != userID {
+		if role != string(domain.RoleAdmin) {
+			return nil, fmt.Errorf("only admins can create delegations for other users")
+		}
+		delegatorID, err = primitive.ObjectIDFromHex(*req.DelegatorID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid delegator ID")
+		}
+	}
+
+	delegator, err := s.userRepo.FindByID(ctx, delegatorID.Hex())
+	if err != nil || delegator.CompanyID != companyObjID {
+		return nil, fmt.Errorf("delegator not found")
+	}
+
+	delegation := &domain.Delegation{
+		CompanyID:   companyObjID,
+		DelegatorID: delegatorID,
+		IsActive:    true,
+		CreatedBy:   userObjID,
+	}
+
+	if err := s.applyRequest(ctx, delegation, req); err != nil {
+		return nil, err
+	}
+	if !delegation.EndDate.After(time.Now()) {
+		return nil, fmt.Errorf("end date must be in the future")
+	}
+
+	if err := s.delegationRepo.Create(ctx, delegation); err != nil {
+		return nil, fmt.Errorf("failed to create delegation: %w", err)
+	}
+
+	return s.respond(ctx, delegation, req.ReassignPending)
+}
+
+// GetDelegations lists the company's delegations for admins and the caller's own for everyone else
+func (s *DelegationService) GetDelegations(ctx context.Context, companyID, userID, role string) ([]*domain.Delegation, error) {
+	var delegations []*domain.Delegation
+	var err error
+	if role == string(domain.RoleAdmin) {
+		delegations, err = s.delegationRepo.FindByCompanyID(ctx, companyID)
+	} else {
+		delegations, err = s.delegationRepo.FindByUserID(ctx, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch delegations: %w", err)
+	}
+	return delegations, nil
+}
+
+// GetDelegation retrieves a delegation visible to the caller
+func (s *DelegationService) GetDelegation(ctx context.Context, companyID, userID, role, delegationID string) (*domain.Delegation, error) {
+	delegation, err := s.delegationRepo.FindByID(ctx, delegationID)
+	if err != nil || delegation.CompanyID.Hex() != companyID {
+		return nil, fmt.Errorf("delegation not found")
+	}
+
+	if role != string(domain.RoleAdmin) &&
+		delegation.DelegatorID.Hex() != userID && delegation.DelegateID.Hex() != userID {
+		return nil, fmt.Errorf("delegation not found")
+	}
+
+	return delegation, nil
+}
+
+// UpdateDelegation changes the delegate, window or scope of a delegation
+func (s *DelegationService) UpdateDelegation(ctx context.Context, companyID, userID, role, delegationID string, req *DelegationRequest) (*DelegationResponse, error) {
+	delegation, err := s.getManageableDelegation(ctx, companyID, userID, role, delegationID)
+	if err != nil {
+		return nil, err
+	}
+	if !delegation.IsActive {
+		return nil, fmt.Errorf("delegation has been revoked")
+	}
+
+	if err := s.applyRequest(ctx, delegation, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.delegationRepo.Update(ctx, delegation); err != nil {
+		return nil, fmt.Errorf("failed to update delegation: %w", err)
+	}
+
+	return s.respond(ctx, delegation, req.ReassignPending)
+}
+
+// RevokeDelegation ends a delegation so new approvals go to the delegator again
+func (s *DelegationService) RevokeDelegation(ctx context.Context, companyID, userID, role, delegationID string) error {
+	delegation, err := s.getManageableDelegation(ctx, companyID, userID, role, delegationID)
+	if err != nil {
+		return err
+	}
+	if !delegation.IsActive {
+		return fmt.Errorf("delegation has already been revoked")
+	}
+
+	delegation.IsActive = false
+	if err := s.delegationRepo.Update(ctx, delegation); err != nil {
+		return fmt.Errorf("failed to revoke delegation: %w", err)
+	}
+
+	return nil
+}
+
+// ReassignPending moves the delegator's open approvals to the delegate of an active delegation
+func (s *DelegationService) ReassignPending(ctx context.Context, companyID, userID, role, delegationID string) (*DelegationResponse, error) {
+	delegation, err := s.getManageableDelegation(ctx, companyID, userID, role, delegationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.respond(ctx, delegation, true)
+}
+
+// respond re-routes pending approvals when asked and the delegation is in effect
+func (s *DelegationService) respond(ctx context.Context, delegation *domain.Delegation, reassign bool) (*DelegationResponse, error) {
+	resp := &DelegationResponse{Delegation: delegation}
+	if !reassign {
+		return resp, nil
+	}
+
+	now := time.Now()
+	if !delegation.IsActive || now.Before(delegation.StartDate) || !now.Before(delegation.EndDate) {
+		return nil, fmt.Errorf("pending approvals can only be reassigned while the delegation is in effect")
+	}
+
+	reassigned, err := s.approvalService.ReassignPendingApprovals(ctx, delegation)
+	if err != nil {
+		return nil, err
+	}
+	resp.Reassigned = reassigned
+
+	return resp, nil
+}
+
+// getManageableDelegation retrieves a delegation the caller may change, which
+// is their own or any delegation of the company for admins
+func (s *DelegationService) getManageableDelegation(ctx context.Context, companyID, userID, role, delegationID string) (*domain.Delegation, error) {
+	delegation, err := s.delegationRepo.FindByID(ctx, delegationID)
+	if err != nil || delegation.CompanyID.Hex() != companyID {
+		return nil, fmt.Errorf("delegation not found")
+	}
+
+	if role != string(domain.RoleAdmin) && delegation.DelegatorID.Hex() != userID {
+		return nil, fmt.Errorf("you can only manage your own delegations")
+	}
+
+	return delegation, nil
+}
+
+// applyRequest validates the request and copies it onto the delegation
+func (s *DelegationService) applyRequest(ctx context.Context, delegation *domain.Delegation, req *DelegationRequest) error {
+	delegateID, err := primitive.ObjectIDFromHex(req.DelegateID)
+	if err != nil {
+		return fmt.Errorf("invalid delegate ID")
+	}
+	if delegateID == delegation.DelegatorID {
+		return fmt.Errorf("cannot delegate approvals to yourself")
+	}
+
+	delegate, err := s.userRepo.FindByID(ctx, req.DelegateID)
+	if err != nil || delegate.CompanyID != delegation.CompanyID {
+		return fmt.Errorf("delegate does not belong to your company")
+	}
+	if !delegate.IsActive {
+		return fmt.Errorf("delegate is inactive")
+	}
+	if delegate.Role != domain.RoleAdmin && delegate.Role != domain.RoleManager {
+		return fmt.Errorf("delegate must be a manager or admin")
+	}
+
+	if req.StartDate.IsZero() || req.EndDate.IsZero() {
+		return fmt.Errorf("start date and end date are required")
+	}
+	if !req.EndDate.After(req.StartDate) {
+		return fmt.Errorf("end date must be after start date")
+	}
+
+	categories := make([]domain.ExpenseCategory, 0, len(req.Categories))
+	for _, category := range req.Categories {
+		if err := validator.ValidateCategory(category); err != nil {
+			return err
+		}
+		categories = append(categories, domain.ExpenseCategory(category))
+	}
+
+	if req.MaxAmount != nil {
+		if err := validator.ValidateAmount(*req.MaxAmount); err != nil {
+			return err
+		}
+	}
+
+	delegation.DelegateID = delegateID
+	delegation.StartDate = req.StartDate
+	delegation.EndDate = req.EndDate
+	delegation.Categories = categories
+	delegation.MaxAmount = req.MaxAmount
+	delegation.Reason = req.Reason
+
+	return nil
+}
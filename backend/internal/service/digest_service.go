@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/notifier"
+)
+
+// digestSendTimeLayout is the layout of a digest send time
+const digestSendTimeLayout = "15:04"
+
+// digestMaxItems bounds how many approvals a digest lists, the counts cover all
+const digestMaxItems = 20
+
+type DigestService struct {
+	approvalRepo domain.ApprovalRepository
+	userRepo     domain.UserRepository
+	companyRepo  domain.CompanyRepository
+	notifier     notifier.Notifier
+	cfg          *config.Config
+}
+
+// NewDigestService creates a new digest service
+func NewDigestService(
+	approvalRepo domain.ApprovalRepository,
+	userRepo domain.UserRepository,
+	companyRepo domain.CompanyRepository,
+	notifier notifier.Notifier,
+	cfg *config.Config,
+) *DigestService {
+	return &DigestService{
+		approvalRepo: approvalRepo,
+		userRepo:     userRepo,
+		companyRepo:  companyRepo,
+		notifier:     notifier,
+		cfg:          cfg,
+	}
+}
+
+// ApprovalDigest summarizes an approver's pending approvals
+type ApprovalDigest struct {
+	Count        int
+	OldestAge    time.Duration // Waiting time of the oldest approval
+	TotalAmount  float64       // In BaseCurrency
+	BaseCurrency string        // Of the approver's company
+	Approvals    []*domain.ApprovalWithDetails
+}
+
+// SendDueDigests sends the digest to every subscriber whose send time passed
+// today in their time zone and who did not get today's digest yet. Approvers
+// without pending approvals get no mail.
+func (s *DigestService) SendDueDigests(ctx context.Context, now time.Time) (int, error) {
+	subscribers, err := s.userRepo.FindDigestSubscribers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, user := range subscribers {
+		localDate, due := s.digestDue(user.Digest, now)
+		if !due {
+			continue
+		}
+
+		// Claiming first keeps a digest from going out twice, a failure
+		// releases the claim so that the next run tries again
+		claimed, err := s.userRepo.ClaimDigest(ctx, user.ID.Hex(), localDate)
+		if err != nil || !claimed {
+			continue
+		}
+
+		digest, err := s.BuildDigest(ctx, user, now)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to build digest for %s: %v\n", user.ID.Hex(), err)
+			s.releaseDigest(ctx, user, localDate)
+			continue
+		}
+		if digest.Count == 0 {
+			continue
+		}
+
+		if err := s.notifier.Send(ctx, composeDigest(user, digest)); err != nil {
+			fmt.Printf("⚠️  Failed to send digest to %s: %v\n", user.ID.Hex(), err)
+			s.releaseDigest(ctx, user, localDate)
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// releaseDigest gives up the claim on the user's digest of localDate
+func (s *DigestService) releaseDigest(ctx context.Context, user *domain.User, localDate string) {
+	if err := s.userRepo.ReleaseDigest(ctx, user.ID.Hex(), localDate, user.Digest.LastSentOn); err != nil {
+		fmt.Printf("⚠️  Failed to release digest of %s: %v\n", user.ID.Hex(), err)
+	}
+}
+
+// BuildDigest summarizes the approver's pending approvals on pending expenses
+// as of now
+func (s *DigestService) BuildDigest(ctx context.Context, approver *domain.User, now time.Time) (*ApprovalDigest, error) {
+	approvals, err := s.approvalRepo.FindPendingByApproverIDWithDetails(ctx, approver.ID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pending approvals: %w", err)
+	}
+
+	digest := &ApprovalDigest{Count: len(approvals)}
+	if company, err := s.companyRepo.FindByID(ctx, approver.CompanyID.Hex()); err == nil {
+		digest.BaseCurrency = company.BaseCurrency
+	}
+
+	sort.Slice(approvals, func(i, j int) bool {
+		return waitingSince(approvals[i]).Before(waitingSince(approvals[j]))
+	})
+
+	for _, approval := range approvals {
+		if approval.Expense != nil {
+			digest.TotalAmount += approval.Expense.ConvertedAmount
+		}
+	}
+	if len(approvals) > 0 {
+		digest.OldestAge = now.Sub(waitingSince(approvals[0]))
+	}
+
+	digest.Approvals = approvals
+	if len(approvals) > digestMaxItems {
+		digest.Approvals = approvals[:digestMaxItems]
+	}
+
+	return digest, nil
+}
+
+// digestDue returns the subscriber's local date and whether today's digest
+// is due
+func (s *DigestService) digestDue(settings *domain.DigestSettings, now time.Time) (string, bool) {
+	if settings == nil || !settings.Enabled {
+		return "", false
+	}
+
+	location, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	sendTime, err := time.Parse(digestSendTimeLayout, settings.SendTime)
+	if err != nil {
+		sendTime, _ = time.Parse(digestSendTimeLayout, s.cfg.Digest.DefaultSendTime)
+	}
+
+	local := now.In(location)
+	localDate := local.Format("2006-01-02")
+	if settings.LastSentOn == localDate {
+		return localDate, false
+	}
+
+	minutes := local.Hour()*60 + local.Minute()
+	return localDate, minutes >= sendTime.Hour()*60+sendTime.Minute()
+}
+
+// waitingSince returns when the approval started waiting for its approver
+func waitingSince(approval *domain.ApprovalWithDetails) time.Time {
+	if approval.AssignedAt != nil {
+		return *approval.AssignedAt
+	}
+	return approval.CreatedAt
+}
+
+// composeDigest renders the digest as a plain text mail
+func composeDigest(approver *domain.User, digest *ApprovalDigest) *notifier.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hello %s,\n\n", approver.FirstName)
+	fmt.Fprintf(&body, "You have %d expenses waiting for your approval, %.2f %s in total.\n",
+		digest.Count, digest.TotalAmount, digest.BaseCurrency)
+	fmt.Fprintf(&body, "The oldest has been waiting for %s.\n\n", formatWaiting(digest.OldestAge))
+
+	for _, approval := range digest.Approvals {
+		if approval.Expense == nil {
+			continue
+		}
+		submitter := ""
+		if approval.Expense.User != nil {
+			submitter = approval.Expense.User.FirstName + " " + approval.Expense.User.LastName
+		}
+		fmt.Fprintf(&body, "- %s: %.2f %s, %s (%s)\n", submitter, approval.Expense.Amount,
+			approval.Expense.Currency, approval.Expense.Category, approval.Expense.Description)
+	}
+	if digest.Count > len(digest.Approvals) {
+		fmt.Fprintf(&body, "- and %d more\n", digest.Count-len(digest.Approvals))
+	}
+
+	return &notifier.Message{
+		To:      approver.Email,
+		Subject: fmt.Sprintf("%d expenses waiting for your approval", digest.Count),
+		Body:    body.String(),
+	}
+}
+
+// formatWaiting renders a waiting time in days or hours
+func formatWaiting(age time.Duration) string {
+	if days := int(age.Hours() / 24); days > 1 {
+		return fmt.Sprintf("%d days", days)
+	}
+	if hours := int(age.Hours()); hours > 1 {
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return "less than two hours"
+}
+
+// normalizeDigestSettings validates the settings a user chose
+func normalizeDigestSettings(settings *domain.DigestSettings) error {
+	settings.SendTime = strings.TrimSpace(settings.SendTime)
+	if _, err := time.Parse(digestSendTimeLayout, settings.SendTime); err != nil {
+		return fmt.Errorf("send_time must be HH:MM")
+	}
+
+	settings.Timezone = strings.TrimSpace(settings.Timezone)
+	if settings.Timezone == "" {
+		settings.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %s", settings.Timezone)
+	}
+
+	return nil
+}
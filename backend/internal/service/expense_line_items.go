@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/validator"
+)
+
+// maxLineItems bounds the lines of one expense
+const maxLineItems = 50
+
+// currencyMinorDigits lists the currencies whose minor unit is not a cent
+var currencyMinorDigits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+type SplitExpenseRequest struct {
+	Allocations []domain.ExpenseLineItem `json:"allocations"`
+}
+
+// minorDigits returns the number of decimals of the currency's minor unit
+func minorDigits(currency string) int {
+	if digits, ok := currencyMinorDigits[strings.ToUpper(currency)]; ok {
+		return digits
+	}
+	return 2
+}
+
+// toMinorUnits rounds amount to whole minor units of the currency, e.g. cents
+func toMinorUnits(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(minorDigits(currency))))
+}
+
+// validateLineItems checks each line of an itemized expense and that the
+// lines sum to the expense amount in the currency's minor units. No lines
+// is valid.
+func validateLineItems(lines []domain.ExpenseLineItem, amount float64, currency string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	if len(lines) > maxLineItems {
+		return fmt.Errorf("an expense can have at most %d line items", maxLineItems)
+	}
+
+	var sum int64
+	for i, line := range lines {
+		if err := validator.ValidateAmount(line.Amount); err != nil {
+			return fmt.Errorf("line item %d: %w", i+1, err)
+		}
+		if err := validator.ValidateCategory(string(line.Category)); err != nil {
+			return fmt.Errorf("line item %d: %w", i+1, err)
+		}
+		if line.Tax < 0 || line.Tax > line.Amount {
+			return fmt.Errorf("line item %d: tax must be between 0 and the line amount", i+1)
+		}
+		if len(line.Description) > 500 {
+			return fmt.Errorf("line item %d: description must be at most 500 characters long", i+1)
+		}
+		sum += toMinorUnits(line.Amount, currency)
+	}
+
+	if total := toMinorUnits(amount, currency); sum != total {
+		digits := minorDigits(currency)
+		return fmt.Errorf("line items sum to %.*f but the expense amount is %.*f",
+			digits, float64(sum)/math.Pow10(digits), digits, amount)
+	}
+	return nil
+}
+
+// convertLineItems converts the expense's lines at its exchange rate
+func convertLineItems(expense *domain.Expense) {
+	for i := range expense.LineItems {
+		expense.LineItems[i].ConvertedAmount = expense.LineItems[i].Amount * expense.ExchangeRate
+	}
+}
+
+// primaryCategory returns the category holding the largest share of the lines
+func primaryCategory(lines []domain.ExpenseLineItem) domain.ExpenseCategory {
+	shares := make(map[domain.ExpenseCategory]float64)
+	var primary domain.ExpenseCategory
+	for _, line := range lines {
+		shares[line.Category] += line.Amount
+		if primary == "" || shares[line.Category] > shares[primary] {
+			primary = line.Category
+		}
+	}
+	return primary
+}
+
+// categoryAmounts returns the expense's amount in base currency per category,
+// per line when it is itemized
+func categoryAmounts(expense *domain.Expense) map[domain.ExpenseCategory]float64 {
+	amounts := make(map[domain.ExpenseCategory]float64)
+	if len(expense.LineItems) == 0 {
+		amounts[expense.Category] = expense.ConvertedAmount
+		return amounts
+	}
+	for _, line := range expense.LineItems {
+		amounts[line.Category] += line.ConvertedAmount
+	}
+	return amounts
+}
+
+// SplitExpense allocates the owner's draft, or expense with requested changes,
+// to several categories. The allocations replace any previous lines and must
+// sum to the expense amount.
+func (s *ExpenseService) SplitExpense(ctx context.Context, expenseID, userID string, req *SplitExpenseRequest) (*domain.Expense, error) {
+	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	if err != nil {
+		return nil, fmt.Errorf("expense not found")
+	}
+
+	if expense.UserID.Hex() != userID {
+		return nil, fmt.Errorf("only the owner can split this expense")
+	}
+	if isReimbursed(expense) {
+		return nil, fmt.Errorf("expense is %s for reimbursement and can no longer be changed", expense.Status)
+	}
+	if frozenByReport(expense) {
+		return nil, inReport(expense)
+	}
+	// Pending expenses were routed by their lines, so only unrouted ones split
+	if expense.Status != domain.StatusDraft && expense.Status != domain.StatusChangesRequested {
+		return nil, fmt.Errorf("cannot split expense that is %s, only drafts and expenses with requested changes can be split", expense.Status)
+	}
+
+	if len(req.Allocations) < 2 {
+		return nil, fmt.Errorf("split an expense into at least 2 allocations")
+	}
+	if expense.Amount <= 0 {
+		return nil, fmt.Errorf("set the expense amount before splitting it")
+	}
+	if err := validateLineItems(req.Allocations, expense.Amount, expense.Currency); err != nil {
+		return nil, err
+	}
+
+	before := auditSnapshot(expense)
+	expense.LineItems = req.Allocations
+	expense.Category = primaryCategory(expense.LineItems)
+	convertLineItems(expense)
+
+	// Fails if an approver acted on the expense since it was read
+	if err := s.expenseRepo.Update(ctx, expense); err != nil {
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			return nil, fmt.Errorf("expense was modified concurrently, reload it and try again")
+		}
+		return nil, fmt.Errorf("failed to split expense: %w", err)
+	}
+
+	s.auditService.Record(ctx, expense.CompanyID, domain.AuditExpenseSplit, auditEntityExpense, expense.ID, before, expense)
+
+	fmt.Printf("✂️  Expense %s split into %d allocations\n", expense.ID.Hex(), len(expense.LineItems))
+
+	s.invalidateExpenseCaches(expense.CompanyID.Hex(), expense.UserID.Hex())
+
+	return expense, nil
+}
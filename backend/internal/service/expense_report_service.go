@@ -0,0 +1,854 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/cache"
+	"expensio-backend/pkg/currency"
+	"expensio-backend/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ExpenseReportService struct {
+	reportRepo      domain.ExpenseReportRepository
+	expenseRepo     domain.ExpenseRepository
+	approvalRepo    domain.ApprovalRepository
+	userRepo        domain.UserRepository
+	companyRepo     domain.CompanyRepository
+	approvalService *ApprovalService
+	auditService    *AuditService
+	cfg             *config.Config
+}
+
+// NewExpenseReportService creates a new expense report service
+func NewExpenseReportService(
+	reportRepo domain.ExpenseReportRepository,
+	expenseRepo domain.ExpenseRepository,
+	approvalRepo domain.ApprovalRepository,
+	userRepo domain.UserRepository,
+	companyRepo domain.CompanyRepository,
+	approvalService *ApprovalService,
+	auditService *AuditService,
+	cfg *config.Config,
+) *ExpenseReportService {
+	return &ExpenseReportService{
+		reportRepo:      reportRepo,
+		expenseRepo:     expenseRepo,
+		approvalRepo:    approvalRepo,
+		userRepo:        userRepo,
+		companyRepo:     companyRepo,
+		approvalService: approvalService,
+		auditService:    auditService,
+		cfg:             cfg,
+	}
+}
+
+type ExpenseReportRequest struct {
+	Title       string    `json:"title"`
+	Purpose     string    `json:"purpose,omitempty"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+type AddReportExpensesRequest struct {
+	ExpenseIDs []string `json:"expense_ids"`
+}
+
+type RejectReportLineRequest struct {
+	Comments string `json:"comments"`
+}
+
+// ExpenseReportDetails is a report together with its member expenses
+type ExpenseReportDetails struct {
+	*domain.ExpenseReport
+	Expenses       []*domain.Expense                  `json:"expenses"`
+	CategoryTotals map[domain.ExpenseCategory]float64 `json:"category_totals"` // In the base currency, per line of itemized expenses
+}
+
+// reportDecisions maps the decision on a report's routing expense to the
+// status of the report and of its member expenses
+var reportDecisions = map[domain.ExpenseStatus]domain.ReportStatus{
+	domain.StatusApproved:         domain.ReportApproved,
+	domain.StatusRejected:         domain.ReportRejected,
+	domain.StatusChangesRequested: domain.ReportChangesRequested,
+}
+
+// frozenByReport reports whether the expense cannot change because its report
+// is in approval, or because it carries a report through approval
+func frozenByReport(expense *domain.Expense) bool {
+	return expense.RoutedReportID != nil || (expense.ReportID != nil && expense.Status == domain.StatusPending)
+}
+
+// inReport refuses to act on an expense on its own when it is decided
+// together with an expense report
+func inReport(expense *domain.Expense) error {
+	if expense.RoutedReportID != nil {
+		return fmt.Errorf("expense stands for expense report %s, act on the report instead", expense.RoutedReportID.Hex())
+	}
+	if expense.ReportID != nil {
+		return fmt.Errorf("expense is part of expense report %s, act on the report instead", expense.ReportID.Hex())
+	}
+	return nil
+}
+
+// CreateReport creates an empty draft report for the user
+func (s *ExpenseReportService) CreateReport(ctx context.Context, userID string, req *ExpenseReportRequest) (*domain.ExpenseReport, error) {
+	if err := validateReportRequest(req); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	company, err := s.companyRepo.FindByID(ctx, user.CompanyID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("company not found")
+	}
+
+	report := &domain.ExpenseReport{
+		CompanyID:    company.ID,
+		UserID:       user.ID,
+		Title:        strings.TrimSpace(req.Title),
+		Purpose:      strings.TrimSpace(req.Purpose),
+		PeriodStart:  req.PeriodStart,
+		PeriodEnd:    req.PeriodEnd,
+		ExpenseIDs:   []primitive.ObjectID{},
+		BaseCurrency: company.BaseCurrency,
+		Status:       domain.ReportDraft,
+	}
+
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, report.CompanyID, domain.AuditReportCreated, auditEntityReport, report.ID, nil, report)
+
+	return report, nil
+}
+
+// GetReports retrieves the user's own reports, or every submitted report of
+// the company for admins and managers
+func (s *ExpenseReportService) GetReports(ctx context.Context, companyID, userID string, company bool) ([]*domain.ExpenseReport, error) {
+	if company {
+		return s.reportRepo.FindByCompanyID(ctx, companyID)
+	}
+	return s.reportRepo.FindByUserID(ctx, userID)
+}
+
+// GetReport retrieves a report with its expenses. Employees only see their
+// own reports and drafts stay private to their owner.
+func (s *ExpenseReportService) GetReport(ctx context.Context, companyID, userID, role, reportID string) (*ExpenseReportDetails, error) {
+	report, err := s.reportRepo.FindByID(ctx, reportID)
+	if err != nil || report.CompanyID.Hex() != companyID {
+		return nil, fmt.Errorf("expense report not found")
+	}
+
+	owner := report.UserID.Hex() == userID
+	if !owner && (report.Status == domain.ReportDraft || (role != "admin" && role != "manager")) {
+		return nil, fmt.Errorf("expense report not found")
+	}
+
+	return s.reportDetails(ctx, report)
+}
+
+// UpdateReport changes the title, purpose and period of the owner's report
+// while it can still be edited
+func (s *ExpenseReportService) UpdateReport(ctx context.Context, userID, reportID string, req *ExpenseReportRequest) (*ExpenseReportDetails, error) {
+	if err := validateReportRequest(req); err != nil {
+		return nil, err
+	}
+
+	report, err := s.editableReport(ctx, userID, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditSnapshot(report)
+	report.Title = strings.TrimSpace(req.Title)
+	report.Purpose = strings.TrimSpace(req.Purpose)
+	report.PeriodStart = req.PeriodStart
+	report.PeriodEnd = req.PeriodEnd
+
+	members, err := s.expenseRepo.FindByReportID(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if !inReportPeriod(report, member.ExpenseDate) {
+			return nil, fmt.Errorf("expense %s is dated outside the new period", member.ID.Hex())
+		}
+	}
+
+	if err := s.updateReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, report.CompanyID, domain.AuditReportUpdated, auditEntityReport, report.ID, before, report)
+
+	return s.reportDetails(ctx, report)
+}
+
+// DeleteReport deletes the owner's report that was never submitted. Its
+// expenses stay as drafts.
+func (s *ExpenseReportService) DeleteReport(ctx context.Context, userID, reportID string) error {
+	report, err := s.ownedReport(ctx, userID, reportID)
+	if err != nil {
+		return err
+	}
+	if report.Status != domain.ReportDraft || report.RoutingExpenseID != nil {
+		return fmt.Errorf("only a report that was never submitted can be deleted")
+	}
+
+	members, err := s.expenseRepo.FindByReportID(ctx, reportID)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if err := s.expenseRepo.RemoveFromReport(ctx, member); err != nil {
+			return fmt.Errorf("failed to release expense %s: %w", member.ID.Hex(), err)
+		}
+	}
+
+	if err := s.reportRepo.Delete(ctx, reportID); err != nil {
+		return err
+	}
+
+	s.auditService.Record(ctx, report.CompanyID, domain.AuditReportDeleted, auditEntityReport, report.ID, report, nil)
+	s.invalidateCaches(report)
+
+	return nil
+}
+
+// AddExpenses adds the owner's drafts to the report. Every expense is checked
+// before the first one is added.
+func (s *ExpenseReportService) AddExpenses(ctx context.Context, userID, reportID string, req *AddReportExpensesRequest) (*ExpenseReportDetails, error) {
+	if len(req.ExpenseIDs) == 0 {
+		return nil, fmt.Errorf("expense_ids is required")
+	}
+
+	report, err := s.editableReport(ctx, userID, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	expenses := make([]*domain.Expense, 0, len(req.ExpenseIDs))
+	seen := make(map[string]bool)
+	for _, expenseID := range req.ExpenseIDs {
+		if seen[expenseID] {
+			continue
+		}
+		seen[expenseID] = true
+
+		expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+		if err != nil || expense.UserID != report.UserID {
+			return nil, fmt.Errorf("expense %s not found", expenseID)
+		}
+		switch {
+		case expense.ReportID != nil:
+			return nil, fmt.Errorf("expense %s is already part of an expense report", expenseID)
+		case expense.Status != domain.StatusDraft:
+			return nil, fmt.Errorf("expense %s is already %s, only drafts can be added", expenseID, expense.Status)
+		case !inReportPeriod(report, expense.ExpenseDate):
+			return nil, fmt.Errorf("expense %s is dated outside the report period", expenseID)
+		}
+		expenses = append(expenses, expense)
+	}
+
+	before := auditSnapshot(report)
+	for _, expense := range expenses {
+		err := s.expenseRepo.AssignToReport(ctx, expense, reportID)
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			err = fmt.Errorf("expense %s was changed by a concurrent request, reload it and try again", expense.ID.Hex())
+		}
+		if err != nil {
+			// Keep the report in line with the expenses added so far
+			_ = s.syncMembers(ctx, report)
+			return nil, err
+		}
+	}
+
+	if err := s.syncMembers(ctx, report); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, report.CompanyID, domain.AuditReportUpdated, auditEntityReport, report.ID, before, report)
+	s.invalidateCaches(report)
+
+	return s.reportDetails(ctx, report)
+}
+
+// RemoveExpense takes an expense out of the owner's report, it becomes a
+// draft again
+func (s *ExpenseReportService) RemoveExpense(ctx context.Context, userID, reportID, expenseID string) (*ExpenseReportDetails, error) {
+	report, err := s.editableReport(ctx, userID, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	if err != nil || expense.ReportID == nil || *expense.ReportID != report.ID {
+		return nil, fmt.Errorf("expense is not part of this report")
+	}
+
+	before := auditSnapshot(report)
+	err = s.expenseRepo.RemoveFromReport(ctx, expense)
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return nil, fmt.Errorf("expense was changed by a concurrent request, reload it and try again")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.syncMembers(ctx, report); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, report.CompanyID, domain.AuditReportUpdated, auditEntityReport, report.ID, before, report)
+	s.invalidateCaches(report)
+
+	return s.reportDetails(ctx, report)
+}
+
+// SubmitReport validates every expense of the owner's report and sends the
+// report through approval as one unit. A report sent back for changes is
+// resubmitted on the approvals it already has.
+func (s *ExpenseReportService) SubmitReport(ctx context.Context, userID, reportID string) (*ExpenseReportDetails, error) {
+	report, err := s.editableReport(ctx, userID, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.expenseRepo.FindByReportID(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("add expenses to the report before submitting it")
+	}
+
+	memberBefore := make([]json.RawMessage, len(members))
+	previous := make(map[primitive.ObjectID]domain.ExpenseStatus, len(members))
+	for i, member := range members {
+		memberBefore[i] = auditSnapshot(member)
+		previous[member.ID] = member.Status
+	}
+
+	// Approvers decide on the amounts at the rate of the day it was submitted
+	for _, member := range members {
+		if member.Status != domain.StatusDraft && member.Status != domain.StatusChangesRequested {
+			return nil, fmt.Errorf("expense %s is already %s", member.ID.Hex(), member.Status)
+		}
+		if err := validateSubmission(member); err != nil {
+			return nil, fmt.Errorf("expense %s: %w", member.ID.Hex(), err)
+		}
+		if !inReportPeriod(report, member.ExpenseDate) {
+			return nil, fmt.Errorf("expense %s is dated outside the report period", member.ID.Hex())
+		}
+		member.ConvertedAmount, member.ExchangeRate, err = currency.ConvertCurrency(member.Amount, member.Currency, report.BaseCurrency, s.cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert currency: %w", err)
+		}
+		convertLineItems(member)
+	}
+	setReportMembers(report, members)
+
+	routing, err := s.routingExpense(ctx, report, members)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refuse before anything changes when the policies leave no way to decide it
+	resubmit := report.RoutingExpenseID != nil
+	if !resubmit {
+		if err := s.approvalService.validateRoute(ctx, routing); err != nil {
+			return nil, fmt.Errorf("cannot route report for approval: %w", err)
+		}
+	}
+
+	from := report.Status
+	before := auditSnapshot(report)
+	if err := s.submitMembers(ctx, report, members, routing); err != nil {
+		return nil, err
+	}
+
+	for i, member := range members {
+		s.auditService.Record(ctx, member.CompanyID, domain.AuditExpenseSubmitted, auditEntityExpense, member.ID, memberBefore[i], member)
+	}
+	if !resubmit {
+		s.auditService.Record(ctx, routing.CompanyID, domain.AuditExpenseCreated, auditEntityExpense, routing.ID, nil, routing)
+	}
+	s.auditService.Record(ctx, report.CompanyID, domain.AuditReportSubmitted, auditEntityReport, report.ID, before, report)
+
+	fmt.Printf("📤 Expense report %s submitted with %d expenses, %.2f %s\n",
+		report.ID.Hex(), len(members), report.TotalAmount, report.BaseCurrency)
+
+	if resubmit {
+		err = s.approvalService.ResubmitExpense(ctx, routing)
+	} else {
+		err = s.approvalService.InitializeApprovals(ctx, routing)
+	}
+	if err != nil {
+		switch {
+		case resubmit:
+			// The routing expense is still awaiting changes, so is the report
+			return nil, s.revertSubmission(ctx, report, from, members, previous, nil, err)
+		case isRoutingError(err):
+			// The route broke since it was checked, hand the report back
+			return nil, s.revertSubmission(ctx, report, from, members, previous, routing, fmt.Errorf("cannot route report for approval: %w", err))
+		}
+		fmt.Printf("⚠️  Warning: Failed to initialize approvals for expense report %s: %v\n", report.ID.Hex(), err)
+	}
+
+	s.invalidateCaches(report)
+
+	// Routing may already have decided the report
+	report, err = s.reportRepo.FindByID(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	return s.reportDetails(ctx, report)
+}
+
+// submitMembers claims the report, moves its expenses to pending and stores
+// the routing expense in one transaction, so that a failure leaves nothing
+// half submitted
+func (s *ExpenseReportService) submitMembers(ctx context.Context, report *domain.ExpenseReport, members []*domain.Expense, routing *domain.Expense) error {
+	resubmit := report.RoutingExpenseID != nil
+
+	// The transaction may run again, each run starts from the state read
+	readReport, readRouting := *report, *routing
+	readMembers := make([]domain.Expense, len(members))
+	for i, member := range members {
+		readMembers[i] = *member
+	}
+
+	now := time.Now()
+	return database.WithTransaction(ctx, func(ctx context.Context) error {
+		*report, *routing = readReport, readRouting
+		for i, member := range members {
+			*member = readMembers[i]
+		}
+
+		report.Status = domain.ReportPending
+		report.SubmittedAt = &now
+		report.DecidedAt = nil
+		report.ApprovedAmount = 0
+		report.RejectedLines = nil
+		if err := s.updateReport(ctx, report); err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			err := s.expenseRepo.Update(ctx, member)
+			if err == nil {
+				err = s.expenseRepo.TransitionStatus(ctx, member, member.Status, domain.StatusPending)
+			}
+			if errors.Is(err, domain.ErrConcurrentUpdate) {
+				return fmt.Errorf("expense %s was changed by a concurrent request, reload it and try again", member.ID.Hex())
+			}
+			if err != nil {
+				return fmt.Errorf("failed to submit expense %s: %w", member.ID.Hex(), err)
+			}
+		}
+
+		if resubmit {
+			if err := s.expenseRepo.Update(ctx, routing); err != nil {
+				return fmt.Errorf("failed to submit expense report: %w", err)
+			}
+			return nil
+		}
+		if err := s.expenseRepo.Create(ctx, routing); err != nil {
+			return fmt.Errorf("failed to submit expense report: %w", err)
+		}
+		report.RoutingExpenseID = &routing.ID
+		return s.updateReport(ctx, report)
+	})
+}
+
+// revertSubmission hands a report whose routing failed back to its owner, with
+// its expenses in the status they were submitted from, and returns cause. A
+// routing expense created by the submission is deleted. The revert is one
+// transaction; when it fails too, both failures are returned.
+func (s *ExpenseReportService) revertSubmission(ctx context.Context, report *domain.ExpenseReport, from domain.ReportStatus, members []*domain.Expense, previous map[primitive.ObjectID]domain.ExpenseStatus, created *domain.Expense, cause error) error {
+	readReport := *report
+	err := database.WithTransaction(ctx, func(ctx context.Context) error {
+		*report = readReport
+		for _, member := range members {
+			if err := s.expenseRepo.TransitionStatus(ctx, member, domain.StatusPending, previous[member.ID]); err != nil {
+				return fmt.Errorf("failed to hand back expense %s: %w", member.ID.Hex(), err)
+			}
+		}
+		if created != nil {
+			if err := s.expenseRepo.Delete(ctx, created.ID.Hex()); err != nil {
+				return err
+			}
+			report.RoutingExpenseID = nil
+		}
+
+		report.Status = from
+		report.SubmittedAt = nil
+		return s.reportRepo.Update(ctx, report)
+	})
+	s.invalidateCaches(report)
+
+	if err != nil {
+		fmt.Printf("⚠️  Failed to hand expense report %s back to its owner: %v\n", report.ID.Hex(), err)
+		return fmt.Errorf("%v; the report could not be handed back and stays submitted: %v", cause, err)
+	}
+	if created != nil {
+		s.auditService.Record(ctx, created.CompanyID, domain.AuditExpenseDeleted, auditEntityExpense, created.ID, created, nil)
+	}
+	return cause
+}
+
+// routingExpense returns the expense that carries the report through
+// approval. It stands for the total in the base currency with one line per
+// category of the report's lines, so that the company's rules check the
+// report per line like any itemized expense.
+func (s *ExpenseReportService) routingExpense(ctx context.Context, report *domain.ExpenseReport, members []*domain.Expense) (*domain.Expense, error) {
+	routing := &domain.Expense{
+		UserID:         report.UserID,
+		CompanyID:      report.CompanyID,
+		Status:         domain.StatusPending,
+		RoutedReportID: &report.ID,
+	}
+	if report.RoutingExpenseID != nil {
+		existing, err := s.expenseRepo.FindByID(ctx, report.RoutingExpenseID.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the report's approval: %w", err)
+		}
+		if existing.Status != domain.StatusChangesRequested {
+			return nil, fmt.Errorf("cannot resubmit report whose approval is %s", existing.Status)
+		}
+		routing = existing
+	}
+
+	routing.LineItems = nil
+	lines := make(map[domain.ExpenseCategory]int)
+	for _, member := range members {
+		memberLines := member.LineItems
+		if len(memberLines) == 0 {
+			memberLines = []domain.ExpenseLineItem{{Amount: member.Amount, Category: member.Category, ConvertedAmount: member.ConvertedAmount}}
+		}
+		for _, line := range memberLines {
+			i, ok := lines[line.Category]
+			if !ok {
+				i = len(routing.LineItems)
+				lines[line.Category] = i
+				routing.LineItems = append(routing.LineItems, domain.ExpenseLineItem{
+					Category:    line.Category,
+					Description: fmt.Sprintf("%s expenses", line.Category),
+				})
+			}
+			// The routing expense is in the base currency
+			tax := 0.0
+			if line.Amount > 0 {
+				tax = line.Tax * line.ConvertedAmount / line.Amount
+			}
+			routing.LineItems[i].Amount += line.ConvertedAmount
+			routing.LineItems[i].ConvertedAmount += line.ConvertedAmount
+			routing.LineItems[i].Tax += tax
+		}
+	}
+	routing.Category = primaryCategory(routing.LineItems)
+
+	routing.Amount = report.TotalAmount
+	routing.Currency = report.BaseCurrency
+	routing.ConvertedAmount = report.TotalAmount
+	routing.ExchangeRate = 1
+	routing.Description = fmt.Sprintf("Expense report: %s", report.Title)
+	routing.ExpenseDate = report.PeriodEnd
+
+	return routing, nil
+}
+
+// settleReport applies the decision taken on a report's routing expense to the
+// report and its member expenses. The decision already happened, so failures
+// are logged rather than returned.
+func (s *ExpenseReportService) settleReport(ctx context.Context, routing *domain.Expense) {
+	status, ok := reportDecisions[routing.Status]
+	if !ok {
+		return
+	}
+	reportID := routing.RoutedReportID.Hex()
+
+	members, err := s.expenseRepo.FindByReportID(ctx, reportID)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to settle expense report %s: %v\n", reportID, err)
+		return
+	}
+	for _, member := range members {
+		before := auditSnapshot(member)
+		err := s.expenseRepo.TransitionStatus(ctx, member, domain.StatusPending, routing.Status)
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Failed to settle expense %s of report %s: %v\n", member.ID.Hex(), reportID, err)
+			continue
+		}
+		if routing.Status != domain.StatusChangesRequested {
+			s.auditService.Record(ctx, member.CompanyID, domain.AuditExpenseDecided, auditEntityExpense, member.ID, before, member)
+		}
+	}
+
+	for attempt := 0; attempt < maxTransitionAttempts; attempt++ {
+		report, err := s.reportRepo.FindByID(ctx, reportID)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to settle expense report %s: %v\n", reportID, err)
+			return
+		}
+
+		before := auditSnapshot(report)
+		now := time.Now()
+		report.Status = status
+		report.DecidedAt = &now
+		if status == domain.ReportApproved {
+			report.ApprovedAmount = report.TotalAmount
+		}
+
+		err = s.reportRepo.Update(ctx, report)
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Failed to settle expense report %s: %v\n", reportID, err)
+			return
+		}
+
+		s.auditService.Record(ctx, report.CompanyID, domain.AuditReportDecided, auditEntityReport, report.ID, before, report)
+		s.invalidateCaches(report)
+
+		fmt.Printf("📋 Expense report %s %s with %d expenses\n", reportID, status, len(members))
+		return
+	}
+
+	fmt.Printf("⚠️  Expense report %s kept changing while its decision was applied\n", reportID)
+}
+
+// RejectLine rejects one expense of an approved report, e.g. a receipt that
+// turns out to be private. Admins and the approvers who approved the report
+// may do so.
+func (s *ExpenseReportService) RejectLine(ctx context.Context, companyID, userID, role, reportID, expenseID string, req *RejectReportLineRequest) (*ExpenseReportDetails, error) {
+	comments := strings.TrimSpace(req.Comments)
+	if comments == "" {
+		return nil, fmt.Errorf("comments are required when rejecting an expense of a report")
+	}
+
+	report, err := s.reportRepo.FindByID(ctx, reportID)
+	if err != nil || report.CompanyID.Hex() != companyID || report.Status == domain.ReportDraft {
+		return nil, fmt.Errorf("expense report not found")
+	}
+	if report.Status != domain.ReportApproved {
+		return nil, fmt.Errorf("only expenses of an approved report can be rejected one by one, the report is %s", report.Status)
+	}
+	if role != "admin" && !s.approvedReport(ctx, report, userID) {
+		return nil, fmt.Errorf("only an admin or an approver of the report can reject its expenses")
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	if err != nil || expense.ReportID == nil || *expense.ReportID != report.ID {
+		return nil, fmt.Errorf("expense is not part of this report")
+	}
+	if expense.Status != domain.StatusApproved {
+		return nil, fmt.Errorf("cannot reject expense that is already %s", expense.Status)
+	}
+
+	expenseBefore := auditSnapshot(expense)
+	err = s.expenseRepo.TransitionStatus(ctx, expense, domain.StatusApproved, domain.StatusRejected)
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return nil, fmt.Errorf("expense was changed by a concurrent request, e.g. scheduled for reimbursement; reload it to see its state")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reject expense: %w", err)
+	}
+	s.auditService.Record(ctx, expense.CompanyID, domain.AuditExpenseDecided, auditEntityExpense, expense.ID, expenseBefore, expense)
+
+	line := domain.ReportLineDecision{
+		ExpenseID: expense.ID,
+		DecidedBy: userObjID,
+		Comments:  comments,
+		DecidedAt: time.Now(),
+	}
+	for attempt := 0; attempt < maxTransitionAttempts; attempt++ {
+		if attempt > 0 {
+			if report, err = s.reportRepo.FindByID(ctx, reportID); err != nil {
+				return nil, err
+			}
+		}
+
+		before := auditSnapshot(report)
+		report.RejectedLines = append(report.RejectedLines, line)
+		report.ApprovedAmount -= expense.ConvertedAmount
+
+		err = s.reportRepo.Update(ctx, report)
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s.auditService.Record(ctx, report.CompanyID, domain.AuditReportLineRejected, auditEntityReport, report.ID, before, report)
+		s.invalidateCaches(report)
+
+		fmt.Printf("❌ Expense %s of report %s rejected by %s\n", expenseID, reportID, userID)
+
+		return s.reportDetails(ctx, report)
+	}
+
+	return nil, fmt.Errorf("the expense was rejected, but the report is being updated by other requests; reload it to see its state")
+}
+
+// approvedReport reports whether the user approved the report's routing
+// expense, themselves or on behalf of someone
+func (s *ExpenseReportService) approvedReport(ctx context.Context, report *domain.ExpenseReport, userID string) bool {
+	if report.RoutingExpenseID == nil {
+		return false
+	}
+	approvals, err := s.approvalRepo.FindByExpenseID(ctx, report.RoutingExpenseID.Hex())
+	if err != nil {
+		return false
+	}
+	for _, approval := range approvals {
+		if approval.Status == domain.ApprovalApproved && approval.ApproverID.Hex() == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// ownedReport returns the user's report
+func (s *ExpenseReportService) ownedReport(ctx context.Context, userID, reportID string) (*domain.ExpenseReport, error) {
+	report, err := s.reportRepo.FindByID(ctx, reportID)
+	if err != nil || report.UserID.Hex() != userID {
+		return nil, fmt.Errorf("expense report not found")
+	}
+	return report, nil
+}
+
+// editableReport returns the user's report while it is a draft or sent back
+// for changes
+func (s *ExpenseReportService) editableReport(ctx context.Context, userID, reportID string) (*domain.ExpenseReport, error) {
+	report, err := s.ownedReport(ctx, userID, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != domain.ReportDraft && report.Status != domain.ReportChangesRequested {
+		return nil, fmt.Errorf("cannot change expense report that is already %s", report.Status)
+	}
+	return report, nil
+}
+
+// syncMembers refreshes the report's expense list and total from its
+// expenses and stores it
+func (s *ExpenseReportService) syncMembers(ctx context.Context, report *domain.ExpenseReport) error {
+	members, err := s.expenseRepo.FindByReportID(ctx, report.ID.Hex())
+	if err != nil {
+		return err
+	}
+	setReportMembers(report, members)
+	return s.updateReport(ctx, report)
+}
+
+// updateReport stores the report unless it was written since it was read
+func (s *ExpenseReportService) updateReport(ctx context.Context, report *domain.ExpenseReport) error {
+	err := s.reportRepo.Update(ctx, report)
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return fmt.Errorf("expense report was modified concurrently, reload it and try again")
+	}
+	return err
+}
+
+// reportDetails loads the report's expenses
+func (s *ExpenseReportService) reportDetails(ctx context.Context, report *domain.ExpenseReport) (*ExpenseReportDetails, error) {
+	members, err := s.expenseRepo.FindByReportID(ctx, report.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if report.Status == domain.ReportDraft || report.Status == domain.ReportChangesRequested {
+		// Totals of a report being edited follow its expenses
+		setReportMembers(report, members)
+	}
+
+	return &ExpenseReportDetails{
+		ExpenseReport:  report,
+		Expenses:       members,
+		CategoryTotals: reportCategoryTotals(members),
+	}, nil
+}
+
+// reportCategoryTotals sums the report's expenses per category, per line of
+// the itemized ones
+func reportCategoryTotals(members []*domain.Expense) map[domain.ExpenseCategory]float64 {
+	totals := make(map[domain.ExpenseCategory]float64)
+	for _, member := range members {
+		for category, amount := range categoryAmounts(member) {
+			totals[category] += amount
+		}
+	}
+	return totals
+}
+
+// setReportMembers sets the report's expense list and total in the base
+// currency from its expenses
+func setReportMembers(report *domain.ExpenseReport, members []*domain.Expense) {
+	report.ExpenseIDs = make([]primitive.ObjectID, 0, len(members))
+	report.TotalAmount = 0
+	for _, member := range members {
+		report.ExpenseIDs = append(report.ExpenseIDs, member.ID)
+		report.TotalAmount += member.ConvertedAmount
+	}
+}
+
+// inReportPeriod reports whether the date falls on a day of the report's
+// period
+func inReportPeriod(report *domain.ExpenseReport, date time.Time) bool {
+	start := report.PeriodStart.UTC().Truncate(24 * time.Hour)
+	end := report.PeriodEnd.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	return !date.Before(start) && date.Before(end)
+}
+
+// validateReportRequest checks the title and period of a report
+func validateReportRequest(req *ExpenseReportRequest) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(title) > 200 {
+		return fmt.Errorf("title must be at most 200 characters long")
+	}
+	if len(req.Purpose) > 500 {
+		return fmt.Errorf("purpose must be at most 500 characters long")
+	}
+	if req.PeriodStart.IsZero() || req.PeriodEnd.IsZero() {
+		return fmt.Errorf("period_start and period_end are required")
+	}
+	if req.PeriodEnd.Before(req.PeriodStart) {
+		return fmt.Errorf("period_end must not be before period_start")
+	}
+	return nil
+}
+
+// invalidateCaches drops the cached expense lists the report's expenses
+// appear in after they changed
+func (s *ExpenseReportService) invalidateCaches(report *domain.ExpenseReport) {
+	_ = cache.DeletePattern(fmt.Sprintf("expenses:user:%s:*", report.UserID.Hex()))
+	_ = cache.DeletePattern(fmt.Sprintf("expenses:company:%s:*", report.CompanyID.Hex()))
+	_ = cache.Delete(fmt.Sprintf("expenses:pending:company:%s", report.CompanyID.Hex()))
+}
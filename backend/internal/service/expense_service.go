@@ -2,13 +2,21 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"expensio-backend/internal/config"
 	"expensio-backend/internal/domain"
 	"expensio-backend/pkg/cache"
 	"expensio-backend/pkg/currency"
+	"expensio-backend/pkg/cursor"
+	"expensio-backend/pkg/notifier"
+	"expensio-backend/pkg/validator"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ExpenseService struct {
@@ -16,6 +24,8 @@ type ExpenseService struct {
 	userRepo        domain.UserRepository
 	companyRepo     domain.CompanyRepository
 	approvalService *ApprovalService
+	auditService    *AuditService
+	notifier        notifier.Notifier
 	cfg             *config.Config
 }
 
@@ -24,13 +34,17 @@ func NewExpenseService(
 	expenseRepo domain.ExpenseRepository,
 	userRepo domain.UserRepository,
 	companyRepo domain.CompanyRepository,
+	auditService *AuditService,
+	notifier notifier.Notifier,
 	cfg *config.Config,
 ) *ExpenseService {
 	return &ExpenseService{
-		expenseRepo: expenseRepo,
-		userRepo:    userRepo,
-		companyRepo: companyRepo,
-		cfg:         cfg,
+		expenseRepo:  expenseRepo,
+		userRepo:     userRepo,
+		companyRepo:  companyRepo,
+		auditService: auditService,
+		notifier:     notifier,
+		cfg:          cfg,
 	}
 }
 
@@ -40,16 +54,19 @@ func (s *ExpenseService) SetApprovalService(approvalService *ApprovalService) {
 }
 
 type CreateExpenseRequest struct {
-	Amount      float64                `json:"amount"`
-	Currency    string                 `json:"currency"`
-	Category    domain.ExpenseCategory `json:"category"`
-	Description string                 `json:"description"`
-	ExpenseDate time.Time              `json:"expense_date"`
-	ReceiptURL  string                 `json:"receipt_url,omitempty"`
-	Merchant    string                 `json:"merchant,omitempty"`
+	Amount      float64                  `json:"amount"`
+	Currency    string                   `json:"currency"`
+	Category    domain.ExpenseCategory   `json:"category"`
+	Description string                   `json:"description"`
+	ExpenseDate time.Time                `json:"expense_date"`
+	ReceiptURL  string                   `json:"receipt_url,omitempty"`
+	Merchant    string                   `json:"merchant,omitempty"`
+	LineItems   []domain.ExpenseLineItem `json:"line_items,omitempty"` // Itemized lines that sum to Amount
+	Draft       bool                     `json:"draft,omitempty"`      // Save without submitting, fields may be incomplete
 }
 
-// CreateExpense creates a new expense with currency conversion
+// CreateExpense creates a new expense with currency conversion. A draft is
+// only saved, its owner submits it later.
 func (s *ExpenseService) CreateExpense(ctx context.Context, userID string, req *CreateExpenseRequest) (*domain.Expense, error) {
 	// Get user
 	user, err := s.userRepo.FindByID(ctx, userID)
@@ -63,15 +80,26 @@ func (s *ExpenseService) CreateExpense(ctx context.Context, userID string, req *
 		return nil, fmt.Errorf("company not found")
 	}
 
+	// Lines of a draft are checked when it is submitted
+	if !req.Draft {
+		if err := validateLineItems(req.LineItems, req.Amount, req.Currency); err != nil {
+			return nil, err
+		}
+	}
+	category := req.Category
+	if category == "" && len(req.LineItems) > 0 {
+		category = primaryCategory(req.LineItems)
+	}
+
 	// Convert currency to company's base currency
-	convertedAmount, exchangeRate, err := currency.ConvertCurrency(
-		req.Amount,
-		req.Currency,
-		company.BaseCurrency,
-		s.cfg,
-	)
+	convertedAmount, exchangeRate, err := s.convertAmount(req, company.BaseCurrency)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert currency: %w", err)
+		return nil, err
+	}
+
+	status := domain.StatusPending
+	if req.Draft {
+		status = domain.StatusDraft
 	}
 
 	// Create expense
@@ -82,14 +110,16 @@ func (s *ExpenseService) CreateExpense(ctx context.Context, userID string, req *
 		Currency:             req.Currency,
 		ConvertedAmount:      convertedAmount,
 		ExchangeRate:         exchangeRate,
-		Category:             req.Category,
+		Category:             category,
 		Description:          req.Description,
 		ExpenseDate:          req.ExpenseDate,
 		ReceiptURL:           req.ReceiptURL,
 		Merchant:             req.Merchant,
-		Status:               domain.StatusPending,
+		LineItems:            req.LineItems,
+		Status:               status,
 		CurrentApprovalLevel: 0,
 	}
+	convertLineItems(expense)
 
 	if err := s.expenseRepo.Create(ctx, expense); err != nil {
 		return nil, fmt.Errorf("failed to create expense: %w", err)
@@ -97,10 +127,23 @@ func (s *ExpenseService) CreateExpense(ctx context.Context, userID string, req *
 
 	fmt.Printf("💰 Expense created: %s (Status: %s)\n", expense.ID.Hex(), expense.Status)
 
+	s.auditService.Record(ctx, expense.CompanyID, domain.AuditExpenseCreated, auditEntityExpense, expense.ID, nil, expense)
+
+	if expense.Status == domain.StatusDraft {
+		s.invalidateExpenseCaches(user.CompanyID.Hex(), userID)
+		return expense, nil
+	}
+
 	// Initialize approval workflow
 	if s.approvalService != nil {
 		fmt.Printf("🔄 Approval service available, initializing approvals...\n")
 		if err := s.approvalService.InitializeApprovals(ctx, expense); err != nil {
+			if isRoutingError(err) {
+				// The expense could never be decided, so do not keep it
+				_ = s.expenseRepo.Delete(ctx, expense.ID.Hex())
+				s.auditService.Record(ctx, expense.CompanyID, domain.AuditExpenseDeleted, auditEntityExpense, expense.ID, expense, nil)
+				return nil, fmt.Errorf("cannot route expense for approval: %w", err)
+			}
 			// Log error but don't fail expense creation
 			fmt.Printf("⚠️  Warning: Failed to initialize approvals for expense %s: %v\n", expense.ID.Hex(), err)
 		}
@@ -122,131 +165,438 @@ func (s *ExpenseService) GetExpenseByID(ctx context.Context, expenseID string) (
 	if err != nil {
 		return nil, fmt.Errorf("expense not found")
 	}
+	// The expense carrying a report through approval is shown as the report
+	if expense.RoutedReportID != nil {
+		return nil, inReport(expense)
+	}
 	return expense, nil
 }
 
 // GetUserExpenses retrieves expenses for a user with pagination and caching
-func (s *ExpenseService) GetUserExpenses(ctx context.Context, userID string, page, limit int) ([]*domain.Expense, int64, error) {
+func (s *ExpenseService) GetUserExpenses(ctx context.Context, userID string, filter *domain.ExpenseFilter, page, limit int) ([]*domain.Expense, int64, error) {
+	// Filtered lists are not cached, only the default listing is
+	cached := isDefaultExpenseFilter(filter)
+
 	// Try cache first
 	cacheKey := fmt.Sprintf("expenses:user:%s:page:%d:limit:%d", userID, page, limit)
 	var cachedData struct {
 		Expenses []*domain.Expense `json:"expenses"`
 		Total    int64             `json:"total"`
 	}
-	err := cache.Get(cacheKey, &cachedData)
-	if err == nil {
-		return cachedData.Expenses, cachedData.Total, nil
+	if cached {
+		if err := cache.Get(cacheKey, &cachedData); err == nil {
+			return cachedData.Expenses, cachedData.Total, nil
+		}
 	}
 
 	// Fetch from database
-	expenses, total, err := s.expenseRepo.FindByUserID(ctx, userID, page, limit)
+	expenses, total, err := s.expenseRepo.FindByUserID(ctx, userID, filter, page, limit)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to fetch expenses: %w", err)
 	}
 
 	// Cache the result
-	cachedData.Expenses = expenses
-	cachedData.Total = total
-	_ = cache.Set(cacheKey, cachedData, s.cfg.Cache.ExpenseListTTL)
+	if cached {
+		cachedData.Expenses = expenses
+		cachedData.Total = total
+		_ = cache.Set(cacheKey, cachedData, s.cfg.Cache.ExpenseListTTL)
+	}
 
 	return expenses, total, nil
 }
 
 // GetCompanyExpenses retrieves all expenses for a company with pagination and caching
-func (s *ExpenseService) GetCompanyExpenses(ctx context.Context, companyID string, page, limit int) ([]*domain.Expense, int64, error) {
+func (s *ExpenseService) GetCompanyExpenses(ctx context.Context, companyID string, filter *domain.ExpenseFilter, page, limit int) ([]*domain.Expense, int64, error) {
+	// Filtered lists are not cached, only the default listing is
+	cached := isDefaultExpenseFilter(filter)
+
 	// Try cache first
 	cacheKey := fmt.Sprintf("expenses:company:%s:page:%d:limit:%d", companyID, page, limit)
 	var cachedData struct {
 		Expenses []*domain.Expense `json:"expenses"`
 		Total    int64             `json:"total"`
 	}
-	err := cache.Get(cacheKey, &cachedData)
-	if err == nil {
-		return cachedData.Expenses, cachedData.Total, nil
+	if cached {
+		if err := cache.Get(cacheKey, &cachedData); err == nil {
+			return cachedData.Expenses, cachedData.Total, nil
+		}
 	}
 
 	// Fetch from database
-	expenses, total, err := s.expenseRepo.FindByCompanyID(ctx, companyID, page, limit)
+	expenses, total, err := s.expenseRepo.FindByCompanyID(ctx, companyID, filter, page, limit)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to fetch expenses: %w", err)
 	}
 
 	// Cache the result
-	cachedData.Expenses = expenses
-	cachedData.Total = total
-	_ = cache.Set(cacheKey, cachedData, s.cfg.Cache.ExpenseListTTL)
+	if cached {
+		cachedData.Expenses = expenses
+		cachedData.Total = total
+		_ = cache.Set(cacheKey, cachedData, s.cfg.Cache.ExpenseListTTL)
+	}
 
 	return expenses, total, nil
 }
 
-// UpdateExpense updates an expense (before approval)
-func (s *ExpenseService) UpdateExpense(ctx context.Context, expenseID string, req *CreateExpenseRequest) error {
+// UpdateExpense updates the owner's expense (before approval)
+func (s *ExpenseService) UpdateExpense(ctx context.Context, expenseID, userID string, req *CreateExpenseRequest) error {
 	// Get existing expense
 	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
 	if err != nil {
 		return fmt.Errorf("expense not found")
 	}
 
-	// Only allow updates if status is pending
-	if expense.Status != domain.StatusPending {
-		return fmt.Errorf("cannot update expense that is already %s", expense.Status)
+	// The expense carrying a report through approval goes with the report
+	if expense.RoutedReportID != nil {
+		return inReport(expense)
+	}
+	if expense.UserID.Hex() != userID {
+		return fmt.Errorf("only the submitter can update this expense")
 	}
 
-	// Get company for currency conversion
-	company, err := s.companyRepo.FindByID(ctx, expense.CompanyID.Hex())
-	if err != nil {
-		return fmt.Errorf("company not found")
+	if isReimbursed(expense) {
+		return fmt.Errorf("expense is %s for reimbursement and can no longer be changed", expense.Status)
 	}
 
-	// Convert currency
-	convertedAmount, exchangeRate, err := currency.ConvertCurrency(
-		req.Amount,
-		req.Currency,
-		company.BaseCurrency,
-		s.cfg,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to convert currency: %w", err)
+	// A report's expenses are frozen while the report is in approval
+	if frozenByReport(expense) {
+		return inReport(expense)
+	}
+
+	// Only drafts and expenses sent back for changes are editable. A pending
+	// expense keeps the route, threshold band and rule it was submitted under,
+	// an approver requests changes to reopen it.
+	switch expense.Status {
+	case domain.StatusDraft, domain.StatusChangesRequested:
+	case domain.StatusPending:
+		return fmt.Errorf("cannot update expense while it is pending approval, an approver has to request changes first")
+	default:
+		return fmt.Errorf("cannot update expense that is already %s", expense.Status)
 	}
 
 	// Update expense fields
+	before := auditSnapshot(expense)
 	expense.Amount = req.Amount
 	expense.Currency = req.Currency
-	expense.ConvertedAmount = convertedAmount
-	expense.ExchangeRate = exchangeRate
 	expense.Category = req.Category
 	expense.Description = req.Description
 	expense.ExpenseDate = req.ExpenseDate
 	expense.ReceiptURL = req.ReceiptURL
 	expense.Merchant = req.Merchant
+	expense.LineItems = req.LineItems
+	if expense.Category == "" && len(expense.LineItems) > 0 {
+		expense.Category = primaryCategory(expense.LineItems)
+	}
+
+	// Only drafts may be incomplete
+	req.Draft = expense.Status == domain.StatusDraft
+	if !req.Draft {
+		if err := validateSubmission(expense); err != nil {
+			return err
+		}
+	}
 
+	// Get company for currency conversion
+	company, err := s.companyRepo.FindByID(ctx, expense.CompanyID.Hex())
+	if err != nil {
+		return fmt.Errorf("company not found")
+	}
+
+	// Convert currency
+	expense.ConvertedAmount, expense.ExchangeRate, err = s.convertAmount(req, company.BaseCurrency)
+	if err != nil {
+		return err
+	}
+	convertLineItems(expense)
+
+	// Fails if the expense was submitted or changed since it was read
 	if err := s.expenseRepo.Update(ctx, expense); err != nil {
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			return fmt.Errorf("expense was modified concurrently, reload it and try again")
+		}
 		return fmt.Errorf("failed to update expense: %w", err)
 	}
 
+	s.auditService.Record(ctx, expense.CompanyID, domain.AuditExpenseUpdated, auditEntityExpense, expense.ID, before, expense)
+
 	// Invalidate caches
 	s.invalidateExpenseCaches(expense.CompanyID.Hex(), expense.UserID.Hex())
 
 	return nil
 }
 
-// DeleteExpense deletes an expense (before approval)
-func (s *ExpenseService) DeleteExpense(ctx context.Context, expenseID string) error {
+// ResubmitExpense sends an expense with requested changes back into approval
+func (s *ExpenseService) ResubmitExpense(ctx context.Context, expenseID, userID string) (*domain.Expense, error) {
+	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	if err != nil {
+		return nil, fmt.Errorf("expense not found")
+	}
+
+	if expense.UserID.Hex() != userID {
+		return nil, fmt.Errorf("only the submitter can resubmit this expense")
+	}
+	if err := inReport(expense); err != nil {
+		return nil, err
+	}
+
+	if s.approvalService == nil {
+		return nil, fmt.Errorf("approval workflow is not available")
+	}
+	if err := s.approvalService.ResubmitExpense(ctx, expense); err != nil {
+		return nil, err
+	}
+
+	// Invalidate caches
+	s.invalidateExpenseCaches(expense.CompanyID.Hex(), expense.UserID.Hex())
+
+	return s.expenseRepo.FindByID(ctx, expenseID)
+}
+
+// SubmitExpense validates the owner's draft, checks that it can be routed
+// under the company's policies and starts its approval workflow
+func (s *ExpenseService) SubmitExpense(ctx context.Context, expenseID, userID string) (*domain.Expense, error) {
+	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	if err != nil {
+		return nil, fmt.Errorf("expense not found")
+	}
+
+	if expense.UserID.Hex() != userID {
+		return nil, fmt.Errorf("only the owner can submit this expense")
+	}
+	if err := inReport(expense); err != nil {
+		return nil, err
+	}
+	if expense.Status != domain.StatusDraft {
+		return nil, fmt.Errorf("cannot submit expense that is already %s", expense.Status)
+	}
+	if err := validateSubmission(expense); err != nil {
+		return nil, err
+	}
+	before := auditSnapshot(expense)
+
+	if s.approvalService == nil {
+		return nil, fmt.Errorf("approval workflow is not available")
+	}
+
+	company, err := s.companyRepo.FindByID(ctx, expense.CompanyID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("company not found")
+	}
+
+	// Approvers decide on the amount at the rate of the day it was submitted
+	expense.ConvertedAmount, expense.ExchangeRate, err = currency.ConvertCurrency(expense.Amount, expense.Currency, company.BaseCurrency, s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert currency: %w", err)
+	}
+	convertLineItems(expense)
+
+	// Refuse before anything changes when the policies leave no way to decide it
+	if err := s.approvalService.validateRoute(ctx, expense); err != nil {
+		return nil, fmt.Errorf("cannot route expense for approval: %w", err)
+	}
+
+	// Fails if the draft was edited or submitted since it was read
+	if err := s.expenseRepo.SubmitDraft(ctx, expense); err != nil {
+		if errors.Is(err, domain.ErrConcurrentUpdate) {
+			return nil, fmt.Errorf("expense was modified concurrently, reload it and try again")
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, fmt.Errorf("expense not found")
+		}
+		return nil, err
+	}
+	s.auditService.Record(ctx, expense.CompanyID, domain.AuditExpenseSubmitted, auditEntityExpense, expense.ID, before, expense)
+
+	fmt.Printf("📤 Draft %s submitted for approval\n", expense.ID.Hex())
+
+	if err := s.approvalService.InitializeApprovals(ctx, expense); err != nil {
+		if isRoutingError(err) {
+			// The route broke since it was checked, hand the draft back
+			revertErr := s.expenseRepo.TransitionStatus(ctx, expense, domain.StatusPending, domain.StatusDraft)
+			s.invalidateExpenseCaches(expense.CompanyID.Hex(), userID)
+			if revertErr != nil {
+				fmt.Printf("⚠️  Failed to hand draft %s back to its owner: %v\n", expense.ID.Hex(), revertErr)
+				return nil, fmt.Errorf("cannot route expense for approval: %v; the expense could not be returned to draft and stays pending: %v", err, revertErr)
+			}
+			return nil, fmt.Errorf("cannot route expense for approval: %w", err)
+		}
+		fmt.Printf("⚠️  Warning: Failed to initialize approvals for expense %s: %v\n", expense.ID.Hex(), err)
+	}
+
+	// Invalidate caches
+	s.invalidateExpenseCaches(expense.CompanyID.Hex(), userID)
+
+	return s.expenseRepo.FindByID(ctx, expenseID)
+}
+
+// FlagStaleDrafts reminds owners of the drafts older than the configured age,
+// once per draft and with one message per owner
+func (s *ExpenseService) FlagStaleDrafts(ctx context.Context, now time.Time) (int, error) {
+	drafts, err := s.expenseRepo.FindStaleDrafts(ctx, now.Add(-s.cfg.Drafts.StaleAfter))
+	if err != nil {
+		return 0, err
+	}
+
+	byOwner := make(map[string][]*domain.Expense)
+	var owners []string
+	for _, draft := range drafts {
+		flagged, err := s.expenseRepo.FlagStaleDraft(ctx, draft)
+		if err != nil || !flagged {
+			continue
+		}
+		ownerID := draft.UserID.Hex()
+		if _, ok := byOwner[ownerID]; !ok {
+			owners = append(owners, ownerID)
+		}
+		byOwner[ownerID] = append(byOwner[ownerID], draft)
+	}
+
+	flagged := 0
+	for _, ownerID := range owners {
+		owned := byOwner[ownerID]
+		flagged += len(owned)
+		s.invalidateExpenseCaches(owned[0].CompanyID.Hex(), ownerID)
+
+		owner, err := s.userRepo.FindByID(ctx, ownerID)
+		if err != nil {
+			continue
+		}
+		if err := s.notifier.Send(ctx, composeDraftReminder(owner, owned)); err != nil {
+			fmt.Printf("⚠️  Failed to remind %s of stale drafts: %v\n", ownerID, err)
+		}
+	}
+
+	return flagged, nil
+}
+
+// composeDraftReminder tells an owner which drafts are still not submitted
+func composeDraftReminder(owner *domain.User, drafts []*domain.Expense) *notifier.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hello %s,\n\n", owner.FirstName)
+	fmt.Fprintf(&body, "You have %d draft expenses that were never submitted:\n\n", len(drafts))
+	for _, draft := range drafts {
+		description := draft.Description
+		if description == "" {
+			description = "no description"
+		}
+		fmt.Fprintf(&body, "- %s, saved on %s\n", description, draft.CreatedAt.Format("2006-01-02"))
+	}
+	body.WriteString("\nSubmit them for approval or delete them.\n")
+
+	return &notifier.Message{
+		To:      owner.Email,
+		Subject: fmt.Sprintf("%d draft expenses waiting to be submitted", len(drafts)),
+		Body:    body.String(),
+	}
+}
+
+// convertAmount converts the requested amount to the company's base currency.
+// A draft without amount or currency stays unconverted until it is complete.
+func (s *ExpenseService) convertAmount(req *CreateExpenseRequest, baseCurrency string) (float64, float64, error) {
+	if req.Draft && (req.Amount <= 0 || req.Currency == "") {
+		return 0, 0, nil
+	}
+
+	convertedAmount, exchangeRate, err := currency.ConvertCurrency(req.Amount, req.Currency, baseCurrency, s.cfg)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to convert currency: %w", err)
+	}
+	return convertedAmount, exchangeRate, nil
+}
+
+// validateSubmission checks an expense like a direct submission is checked
+func validateSubmission(expense *domain.Expense) error {
+	if err := validator.ValidateAmount(expense.Amount); err != nil {
+		return err
+	}
+	if err := validator.ValidateCurrency(expense.Currency); err != nil {
+		return err
+	}
+	if err := validator.ValidateCategory(string(expense.Category)); err != nil {
+		return err
+	}
+	if err := validator.ValidateExpenseDate(expense.ExpenseDate); err != nil {
+		return err
+	}
+	if err := validateLineItems(expense.LineItems, expense.Amount, expense.Currency); err != nil {
+		return err
+	}
+	return validator.ValidateDescription(expense.Description)
+}
+
+// WithdrawExpense takes the submitter's pending expense out of approval
+func (s *ExpenseService) WithdrawExpense(ctx context.Context, expenseID, userID string) (*domain.Expense, error) {
+	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	if err != nil {
+		return nil, fmt.Errorf("expense not found")
+	}
+
+	if expense.UserID.Hex() != userID {
+		return nil, fmt.Errorf("only the submitter can withdraw this expense")
+	}
+	if err := inReport(expense); err != nil {
+		return nil, err
+	}
+
+	if s.approvalService == nil {
+		return nil, fmt.Errorf("approval workflow is not available")
+	}
+	if err := s.approvalService.WithdrawExpense(ctx, expense); err != nil {
+		return nil, err
+	}
+
+	// Invalidate caches
+	s.invalidateExpenseCaches(expense.CompanyID.Hex(), expense.UserID.Hex())
+
+	return expense, nil
+}
+
+// DeleteExpense deletes the owner's expense (before approval)
+func (s *ExpenseService) DeleteExpense(ctx context.Context, expenseID, userID string) error {
 	// Get existing expense
 	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
 	if err != nil {
 		return fmt.Errorf("expense not found")
 	}
 
-	// Only allow deletion if status is pending
-	if expense.Status != domain.StatusPending {
+	// The expense carrying a report through approval goes with the report
+	if expense.RoutedReportID != nil {
+		return inReport(expense)
+	}
+	if expense.UserID.Hex() != userID {
+		return fmt.Errorf("only the submitter can delete this expense")
+	}
+
+	if isReimbursed(expense) {
+		return fmt.Errorf("expense is %s for reimbursement and can no longer be changed", expense.Status)
+	}
+	if expense.ReportID != nil {
+		return fmt.Errorf("expense is part of expense report %s, remove it from the report first", expense.ReportID.Hex())
+	}
+	if err := inReport(expense); err != nil {
+		return err
+	}
+
+	// Only allow deletion before a decision
+	switch expense.Status {
+	case domain.StatusDraft, domain.StatusPending, domain.StatusChangesRequested, domain.StatusWithdrawn:
+	default:
 		return fmt.Errorf("cannot delete expense that is already %s", expense.Status)
 	}
 
+	// Approvals go with the expense, out of every approver's queue. Drafts have none.
+	if s.approvalService != nil && expense.Status != domain.StatusDraft {
+		if err := s.approvalService.DeleteExpenseApprovals(ctx, expense); err != nil {
+			return err
+		}
+	}
+
 	if err := s.expenseRepo.Delete(ctx, expenseID); err != nil {
 		return fmt.Errorf("failed to delete expense: %w", err)
 	}
 
+	s.auditService.Record(ctx, expense.CompanyID, domain.AuditExpenseDeleted, auditEntityExpense, expense.ID, expense, nil)
+
 	// Invalidate caches
 	s.invalidateExpenseCaches(expense.CompanyID.Hex(), expense.UserID.Hex())
 
@@ -275,6 +625,82 @@ func (s *ExpenseService) GetPendingExpenses(ctx context.Context, companyID strin
 	return expenses, nil
 }
 
+// expenseCursorScope keeps cursors of other lists out of expense lists
+const expenseCursorScope = "expenses"
+
+// GetUserExpensesAfter retrieves the user's expenses after the cursor, an
+// empty cursor starts at the first. It also returns the cursor of the next
+// page, empty on the last page.
+func (s *ExpenseService) GetUserExpensesAfter(ctx context.Context, userID string, filter *domain.ExpenseFilter, cursorToken string, limit int) ([]*domain.Expense, string, error) {
+	return s.expensesAfter(filter, cursorToken, limit, func(after *domain.PageCursor) ([]*domain.Expense, error) {
+		return s.expenseRepo.FindByUserIDAfter(ctx, userID, filter, after, limit+1)
+	})
+}
+
+// GetCompanyExpensesAfter retrieves the company's expenses after the cursor
+// like GetUserExpensesAfter
+func (s *ExpenseService) GetCompanyExpensesAfter(ctx context.Context, companyID string, filter *domain.ExpenseFilter, cursorToken string, limit int) ([]*domain.Expense, string, error) {
+	return s.expensesAfter(filter, cursorToken, limit, func(after *domain.PageCursor) ([]*domain.Expense, error) {
+		return s.expenseRepo.FindByCompanyIDAfter(ctx, companyID, filter, after, limit+1)
+	})
+}
+
+// expensesAfter decodes the cursor, fetches one item more than the page to
+// learn whether another page follows, and issues its cursor
+func (s *ExpenseService) expensesAfter(filter *domain.ExpenseFilter, cursorToken string, limit int, fetch func(after *domain.PageCursor) ([]*domain.Expense, error)) ([]*domain.Expense, string, error) {
+	if filter.SortBy != "" {
+		return nil, "", fmt.Errorf("cursor pagination only supports sorting by created_at")
+	}
+
+	var after *domain.PageCursor
+	if cursorToken != "" {
+		position, err := cursor.Decode(cursorToken, expenseCursorScope, s.cfg)
+		if err != nil || position.Asc != filter.SortAsc {
+			return nil, "", fmt.Errorf("invalid cursor")
+		}
+		id, err := primitive.ObjectIDFromHex(position.ID)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid cursor")
+		}
+		after = &domain.PageCursor{CreatedAt: position.CreatedAt, ID: id}
+	}
+
+	expenses, err := fetch(after)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch expenses: %w", err)
+	}
+
+	if len(expenses) <= limit {
+		return expenses, "", nil
+	}
+	expenses = expenses[:limit]
+	return expenses, s.ExpenseCursor(filter, expenses[limit-1]), nil
+}
+
+// ExpenseCursor returns the cursor continuing a list after expense. Only
+// lists in created_at order have one.
+func (s *ExpenseService) ExpenseCursor(filter *domain.ExpenseFilter, expense *domain.Expense) string {
+	if filter != nil && filter.SortBy != "" {
+		return ""
+	}
+
+	token, err := cursor.Encode(&cursor.Cursor{
+		Scope:     expenseCursorScope,
+		CreatedAt: expense.CreatedAt,
+		ID:        expense.ID.Hex(),
+		Asc:       filter != nil && filter.SortAsc,
+	}, s.cfg)
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// isDefaultExpenseFilter reports whether the filter leaves the listing as is
+func isDefaultExpenseFilter(filter *domain.ExpenseFilter) bool {
+	return filter == nil || reflect.DeepEqual(*filter, domain.ExpenseFilter{})
+}
+
 // invalidateExpenseCaches invalidates all expense-related caches
 func (s *ExpenseService) invalidateExpenseCaches(companyID, userID string) {
 	// Invalidate user expense caches
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"expensio-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNonOwnerCannotChangeExpense(t *testing.T) {
+	for _, status := range []domain.ExpenseStatus{domain.StatusDraft, domain.StatusChangesRequested} {
+		expense := domain.Expense{
+			ID:          primitive.NewObjectID(),
+			CompanyID:   primitive.NewObjectID(),
+			UserID:      primitive.NewObjectID(),
+			Amount:      42,
+			Description: "Taxi to the airport",
+			Status:      status,
+		}
+		repo := &memExpenseRepo{expenses: map[primitive.ObjectID]domain.Expense{expense.ID: expense}}
+		s := NewExpenseService(repo, nil, nil, NewAuditService(&memAuditRepo{}, nil), nil, nil)
+		stranger := primitive.NewObjectID().Hex()
+
+		if err := s.UpdateExpense(context.Background(), expense.ID.Hex(), stranger, &CreateExpenseRequest{Amount: 4200}); err == nil {
+			t.Errorf("%s: a non-owner updated the expense", status)
+		}
+		if err := s.DeleteExpense(context.Background(), expense.ID.Hex(), stranger); err == nil {
+			t.Errorf("%s: a non-owner deleted the expense", status)
+		}
+
+		stored, err := repo.FindByID(context.Background(), expense.ID.Hex())
+		if err != nil {
+			t.Fatalf("%s: expense is gone: %v", status, err)
+		}
+		if stored.Amount != expense.Amount || stored.Version != expense.Version {
+			t.Errorf("%s: want the expense unchanged, got amount %v version %d", status, stored.Amount, stored.Version)
+		}
+	}
+}
+
+func TestRoutingExpenseIsReachedThroughItsReport(t *testing.T) {
+	reportID := primitive.NewObjectID()
+	routing := domain.Expense{
+		ID:             primitive.NewObjectID(),
+		CompanyID:      primitive.NewObjectID(),
+		UserID:         primitive.NewObjectID(),
+		Amount:         300,
+		Status:         domain.StatusPending,
+		RoutedReportID: &reportID,
+	}
+	repo := &memExpenseRepo{expenses: map[primitive.ObjectID]domain.Expense{routing.ID: routing}}
+	s := NewExpenseService(repo, nil, nil, NewAuditService(&memAuditRepo{}, nil), nil, nil)
+	owner := routing.UserID.Hex()
+
+	if _, err := s.GetExpenseByID(context.Background(), routing.ID.Hex()); err == nil {
+		t.Error("the report's routing expense was returned as an expense")
+	}
+	if err := s.UpdateExpense(context.Background(), routing.ID.Hex(), owner, &CreateExpenseRequest{Amount: 1}); err == nil {
+		t.Error("the report owner updated the report's routing expense")
+	}
+	if err := s.DeleteExpense(context.Background(), routing.ID.Hex(), owner); err == nil {
+		t.Error("the report owner deleted the report's routing expense")
+	}
+
+	stored, err := repo.FindByID(context.Background(), routing.ID.Hex())
+	if err != nil {
+		t.Fatalf("routing expense is gone: %v", err)
+	}
+	if stored.Amount != routing.Amount || stored.Status != routing.Status {
+		t.Errorf("want the routing expense unchanged, got amount %v status %s", stored.Amount, stored.Status)
+	}
+}
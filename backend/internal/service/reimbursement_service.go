@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/domain"
+	"expensio-backend/pkg/cache"
+	"expensio-backend/pkg/database"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReimbursementService struct {
+	batchRepo    domain.ReimbursementBatchRepository
+	expenseRepo  domain.ExpenseRepository
+	userRepo     domain.UserRepository
+	auditService *AuditService
+	cfg          *config.Config
+}
+
+// NewReimbursementService creates a new reimbursement service
+func NewReimbursementService(
+	batchRepo domain.ReimbursementBatchRepository,
+	expenseRepo domain.ExpenseRepository,
+	userRepo domain.UserRepository,
+	auditService *AuditService,
+	cfg *config.Config,
+) *ReimbursementService {
+	return &ReimbursementService{
+		batchRepo:    batchRepo,
+		expenseRepo:  expenseRepo,
+		userRepo:     userRepo,
+		auditService: auditService,
+		cfg:          cfg,
+	}
+}
+
+type CreateBatchesRequest struct {
+	UserID string `json:"user_id,omitempty"` // Defaults to every employee with approved expenses
+}
+
+type PayBatchRequest struct {
+	PaymentDate      time.Time `json:"payment_date"`
+	PaymentReference string    `json:"payment_reference"`
+}
+
+// batchKey groups expenses that are reimbursed together
+type batchKey struct {
+	userID   primitive.ObjectID
+	currency string
+}
+
+// isReimbursed reports whether the expense is scheduled or paid out, after
+// which it must not change anymore
+func isReimbursed(expense *domain.Expense) bool {
+	return expense.Status == domain.StatusScheduled || expense.Status == domain.StatusPaid
+}
+
+// CreateBatches schedules the company's approved expenses for reimbursement,
+// one batch per employee and currency
+func (s *ReimbursementService) CreateBatches(ctx context.Context, companyID, createdBy string, req *CreateBatchesRequest) ([]*domain.ReimbursementBatch, error) {
+	companyObjID, err := primitive.ObjectIDFromHex(companyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid company ID")
+	}
+	createdByID, err := primitive.ObjectIDFromHex(createdBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	if req.UserID != "" {
+		user, err := s.userRepo.FindByID(ctx, req.UserID)
+		if err != nil || user.CompanyID != companyObjID {
+			return nil, fmt.Errorf("user not found")
+		}
+	}
+
+	expenses, err := s.expenseRepo.FindApprovedUnbatchedByCompanyID(ctx, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch approved expenses: %w", err)
+	}
+
+	groups := make(map[batchKey][]*domain.Expense)
+	var keys []batchKey
+	for _, expense := range expenses {
+		if req.UserID != "" && expense.UserID.Hex() != req.UserID {
+			continue
+		}
+		key := batchKey{userID: expense.UserID, currency: expense.Currency}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], expense)
+	}
+
+	batches := make([]*domain.ReimbursementBatch, 0, len(keys))
+	for _, key := range keys {
+		batch, err := s.createBatch(ctx, companyObjID, createdByID, key, groups[key])
+		if err != nil {
+			return batches, err
+		}
+		if batch != nil {
+			batches = append(batches, batch)
+		}
+	}
+
+	for _, batch := range batches {
+		s.invalidateCaches(companyID, batch.UserID.Hex())
+	}
+
+	return batches, nil
+}
+
+// createBatch claims the expenses for a new batch and stores it in one
+// transaction. Expenses a concurrent request scheduled first are left out,
+// nil means none was left.
+func (s *ReimbursementService) createBatch(ctx context.Context, companyID, createdBy primitive.ObjectID, key batchKey, expenses []*domain.Expense) (*domain.ReimbursementBatch, error) {
+	var (
+		batch     *domain.ReimbursementBatch
+		scheduled []*domain.Expense
+	)
+	err := database.WithTransaction(ctx, func(ctx context.Context) error {
+		batch = &domain.ReimbursementBatch{
+			ID:        primitive.NewObjectID(),
+			CompanyID: companyID,
+			UserID:    key.userID,
+			Currency:  key.currency,
+			Status:    domain.BatchScheduled,
+			CreatedBy: createdBy,
+		}
+		scheduled = scheduled[:0]
+
+		for _, expense := range expenses {
+			// Work on a copy, the transaction may run again
+			claimed := *expense
+			err := s.expenseRepo.AssignToBatch(ctx, &claimed, batch.ID.Hex())
+			if errors.Is(err, domain.ErrConcurrentUpdate) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to schedule expense: %w", err)
+			}
+			scheduled = append(scheduled, &claimed)
+
+			batch.ExpenseIDs = append(batch.ExpenseIDs, expense.ID)
+			batch.TotalAmount += expense.Amount
+		}
+
+		if len(batch.ExpenseIDs) == 0 {
+			return nil
+		}
+		return s.batchRepo.Create(ctx, batch)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(batch.ExpenseIDs) == 0 {
+		return nil, nil
+	}
+
+	before := make(map[primitive.ObjectID]*domain.Expense, len(expenses))
+	for _, expense := range expenses {
+		before[expense.ID] = expense
+	}
+	for _, expense := range scheduled {
+		s.auditService.Record(ctx, companyID, domain.AuditExpenseScheduled, auditEntityExpense, expense.ID, before[expense.ID], expense)
+	}
+	s.auditService.Record(ctx, companyID, domain.AuditBatchCreated, auditEntityBatch, batch.ID, nil, batch)
+
+	fmt.Printf("💸 Reimbursement batch %s scheduled: %d expenses, %.2f %s\n",
+		batch.ID.Hex(), len(batch.ExpenseIDs), batch.TotalAmount, batch.Currency)
+
+	return batch, nil
+}
+
+// GetBatches retrieves the company's reimbursement batches
+func (s *ReimbursementService) GetBatches(ctx context.Context, companyID string) ([]*domain.ReimbursementBatch, error) {
+	return s.batchRepo.FindByCompanyID(ctx, companyID)
+}
+
+// GetBatch retrieves a reimbursement batch of the company
+func (s *ReimbursementService) GetBatch(ctx context.Context, companyID, batchID string) (*domain.ReimbursementBatch, error) {
+	batch, err := s.batchRepo.FindByID(ctx, batchID)
+	if err != nil || batch.CompanyID.Hex() != companyID {
+		return nil, fmt.Errorf("reimbursement batch not found")
+	}
+	return batch, nil
+}
+
+// PayBatch records the payment of a batch and moves all its expenses to
+// paid in one transaction
+func (s *ReimbursementService) PayBatch(ctx context.Context, companyID, paidBy, batchID string, req *PayBatchRequest) (*domain.ReimbursementBatch, error) {
+	batch, err := s.GetBatch(ctx, companyID, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	paidByID, err := primitive.ObjectIDFromHex(paidBy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID")
+	}
+
+	if req.PaymentDate.IsZero() {
+		return nil, fmt.Errorf("payment_date is required")
+	}
+	reference := strings.TrimSpace(req.PaymentReference)
+	if reference == "" {
+		return nil, fmt.Errorf("payment_reference is required")
+	}
+
+	if batch.Status == domain.BatchPaid {
+		return nil, fmt.Errorf("reimbursement batch is already paid")
+	}
+
+	before := auditSnapshot(batch)
+	batch.PaymentDate = &req.PaymentDate
+	batch.PaymentReference = reference
+	batch.PaidBy = &paidByID
+
+	var moved int64
+	err = database.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.batchRepo.MarkPaid(ctx, batch); err != nil {
+			return err
+		}
+		paid, err := s.expenseRepo.MarkBatchPaid(ctx, batch.ID.Hex())
+		moved = paid
+		return err
+	})
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return nil, fmt.Errorf("reimbursement batch was paid by a concurrent request, reload it to see its state")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(ctx, batch.CompanyID, domain.AuditBatchPaid, auditEntityBatch, batch.ID, before, batch)
+
+	fmt.Printf("✅ Reimbursement batch %s paid (%s), %d expenses moved to paid\n",
+		batch.ID.Hex(), batch.PaymentReference, moved)
+
+	s.invalidateCaches(companyID, batch.UserID.Hex())
+
+	return batch, nil
+}
+
+// invalidateCaches drops the cached expense lists of the company and the
+// reimbursed employee after their expenses changed status
+func (s *ReimbursementService) invalidateCaches(companyID, userID string) {
+	_ = cache.DeletePattern(fmt.Sprintf("expenses:user:%s:*", userID))
+	_ = cache.DeletePattern(fmt.Sprintf("expenses:company:%s:*", companyID))
+}
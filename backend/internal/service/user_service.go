@@ -13,27 +13,31 @@ import (
 )
 
 type UserService struct {
-	userRepo    domain.UserRepository
-	companyRepo domain.CompanyRepository
-	cfg         *config.Config
+	userRepo     domain.UserRepository
+	companyRepo  domain.CompanyRepository
+	auditService *AuditService
+	cfg          *config.Config
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo domain.UserRepository, companyRepo domain.CompanyRepository, cfg *config.Config) *UserService {
+func NewUserService(userRepo domain.UserRepository, companyRepo domain.CompanyRepository, auditService *AuditService, cfg *config.Config) *UserService {
 	return &UserService{
-		userRepo:    userRepo,
-		companyRepo: companyRepo,
-		cfg:         cfg,
+		userRepo:     userRepo,
+		companyRepo:  companyRepo,
+		auditService: auditService,
+		cfg:          cfg,
 	}
 }
 
 type CreateUserRequest struct {
-	Email     string          `json:"email"`
-	Password  string          `json:"password"`
-	FirstName string          `json:"first_name"`
-	LastName  string          `json:"last_name"`
-	Role      domain.UserRole `json:"role"`
-	ManagerID *string         `json:"manager_id,omitempty"`
+	Email         string          `json:"email"`
+	Password      string          `json:"password"`
+	FirstName     string          `json:"first_name"`
+	LastName      string          `json:"last_name"`
+	Role          domain.UserRole `json:"role"`
+	ManagerID     *string         `json:"manager_id,omitempty"`
+	ApprovalLimit *float64        `json:"approval_limit,omitempty"` // Signing limit in company base currency
+	Department    string          `json:"department,omitempty"`
 }
 
 // CreateUser creates a new user (Admin only)
@@ -67,13 +71,14 @@ func (s *UserService) CreateUser(ctx context.Context, companyID string, req *Cre
 
 	// Create user
 	user := &domain.User{
-		Email:     req.Email,
-		Password:  string(hashedPassword),
-		FirstName: req.FirstName,
-		LastName:  req.LastName,
-		Role:      req.Role,
-		CompanyID: companyObjID,
-		IsActive:  true,
+		Email:      req.Email,
+		Password:   string(hashedPassword),
+		FirstName:  req.FirstName,
+		LastName:   req.LastName,
+		Role:       req.Role,
+		CompanyID:  companyObjID,
+		Department: req.Department,
+		IsActive:   true,
 	}
 
 	// Set manager if provided
@@ -96,6 +101,13 @@ func (s *UserService) CreateUser(ctx context.Context, companyID string, req *Cre
 		user.ManagerID = &managerObjID
 	}
 
+	if req.ApprovalLimit != nil {
+		if *req.ApprovalLimit < 0 {
+			return nil, fmt.Errorf("approval limit cannot be negative")
+		}
+		user.ApprovalLimit = req.ApprovalLimit
+	}
+
 	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
@@ -151,6 +163,9 @@ func (s *UserService) UpdateRole(ctx context.Context, userID string, newRole dom
 		return fmt.Errorf("failed to update role: %w", err)
 	}
 
+	s.auditService.Record(ctx, user.CompanyID, domain.AuditUserRoleChanged, auditEntityUser, user.ID,
+		map[string]domain.UserRole{"role": user.Role}, map[string]domain.UserRole{"role": newRole})
+
 	// Invalidate caches
 	cacheKey := fmt.Sprintf("users:company:%s", user.CompanyID.Hex())
 	_ = cache.Delete(cacheKey)
@@ -193,6 +208,49 @@ func (s *UserService) AssignManager(ctx context.Context, userID, managerID strin
 	return nil
 }
 
+// UpdateApprovalLimit sets or, with nil, clears a user's approval limit (Admin only)
+func (s *UserService) UpdateApprovalLimit(ctx context.Context, userID string, limit *float64) error {
+	// Validate user exists
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	if limit != nil && *limit < 0 {
+		return fmt.Errorf("approval limit cannot be negative")
+	}
+
+	if err := s.userRepo.UpdateApprovalLimit(ctx, userID, limit); err != nil {
+		return fmt.Errorf("failed to update approval limit: %w", err)
+	}
+
+	// Invalidate cache
+	cacheKey := fmt.Sprintf("users:company:%s", user.CompanyID.Hex())
+	_ = cache.Delete(cacheKey)
+
+	return nil
+}
+
+// UpdateDepartment moves a user to another department (Admin only)
+func (s *UserService) UpdateDepartment(ctx context.Context, userID, department string) error {
+	// Validate user exists
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	user.Department = department
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update department: %w", err)
+	}
+
+	// Invalidate cache
+	cacheKey := fmt.Sprintf("users:company:%s", user.CompanyID.Hex())
+	_ = cache.Delete(cacheKey)
+
+	return nil
+}
+
 // DeleteUser deletes a user (Admin only)
 func (s *UserService) DeleteUser(ctx context.Context, userID string) error {
 	// Validate user exists
@@ -215,3 +273,36 @@ func (s *UserService) DeleteUser(ctx context.Context, userID string) error {
 
 	return nil
 }
+
+// GetDigestSettings returns the user's digest settings, off by default
+func (s *UserService) GetDigestSettings(ctx context.Context, userID string) (*domain.DigestSettings, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if user.Digest == nil {
+		return &domain.DigestSettings{
+			SendTime: s.cfg.Digest.DefaultSendTime,
+			Timezone: "UTC",
+		}, nil
+	}
+	return user.Digest, nil
+}
+
+// UpdateDigestSettings opts the user in or out of the daily digest and sets
+// when it is sent in their time zone
+func (s *UserService) UpdateDigestSettings(ctx context.Context, userID string, settings *domain.DigestSettings) (*domain.DigestSettings, error) {
+	if settings.SendTime == "" {
+		settings.SendTime = s.cfg.Digest.DefaultSendTime
+	}
+	if err := normalizeDigestSettings(settings); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateDigestSettings(ctx, userID, settings); err != nil {
+		return nil, err
+	}
+
+	return s.GetDigestSettings(ctx, userID)
+}
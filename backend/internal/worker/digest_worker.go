@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"log"
+
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/service"
+)
+
+const digestLockKey = "locks:worker:digest"
+
+// DigestWorker periodically sends the daily digests that became due
+type DigestWorker struct {
+	digestService *service.DigestService
+	cfg           *config.Config
+}
+
+// NewDigestWorker creates a new digest worker
+func NewDigestWorker(digestService *service.DigestService, cfg *config.Config) *DigestWorker {
+	return &DigestWorker{
+		digestService: digestService,
+		cfg:           cfg,
+	}
+}
+
+// Start runs the worker in the background until ctx is cancelled
+func (w *DigestWorker) Start(ctx context.Context) {
+	if !w.cfg.Digest.Enabled || w.cfg.Digest.Interval <= 0 {
+		log.Println("⏸️  Approval digest worker disabled")
+		return
+	}
+
+	newPeriodicRunner("Approval digest worker", digestLockKey, w.cfg.Digest.Interval,
+		w.digestService.SendDueDigests, "📬 Sent %d approval digests").Start(ctx)
+}
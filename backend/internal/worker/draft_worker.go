@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"log"
+
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/service"
+)
+
+const draftLockKey = "locks:worker:drafts"
+
+// DraftWorker periodically reminds owners of drafts they never submitted
+type DraftWorker struct {
+	expenseService *service.ExpenseService
+	cfg            *config.Config
+}
+
+// NewDraftWorker creates a new draft worker
+func NewDraftWorker(expenseService *service.ExpenseService, cfg *config.Config) *DraftWorker {
+	return &DraftWorker{
+		expenseService: expenseService,
+		cfg:            cfg,
+	}
+}
+
+// Start runs the worker in the background until ctx is cancelled
+func (w *DraftWorker) Start(ctx context.Context) {
+	if w.cfg.Drafts.CheckInterval <= 0 || w.cfg.Drafts.StaleAfter <= 0 {
+		log.Println("⏸️  Stale draft worker disabled")
+		return
+	}
+
+	newPeriodicRunner("Stale draft worker", draftLockKey, w.cfg.Drafts.CheckInterval,
+		w.expenseService.FlagStaleDrafts, "📝 Reminded owners of %d stale drafts").Start(ctx)
+}
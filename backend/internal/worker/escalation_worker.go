@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"log"
+
+	"expensio-backend/internal/config"
+	"expensio-backend/internal/service"
+)
+
+const escalationLockKey = "locks:worker:escalation"
+
+// EscalationWorker periodically escalates approvals that exceeded their SLA
+type EscalationWorker struct {
+	approvalService *service.ApprovalService
+	cfg             *config.Config
+}
+
+// NewEscalationWorker creates a new escalation worker
+func NewEscalationWorker(approvalService *service.ApprovalService, cfg *config.Config) *EscalationWorker {
+	return &EscalationWorker{
+		approvalService: approvalService,
+		cfg:             cfg,
+	}
+}
+
+// Start runs the worker in the background until ctx is cancelled
+func (w *EscalationWorker) Start(ctx context.Context) {
+	if !w.cfg.Escalation.Enabled || w.cfg.Escalation.Interval <= 0 {
+		log.Println("⏸️  Approval escalation worker disabled")
+		return
+	}
+
+	newPeriodicRunner("Approval escalation worker", escalationLockKey, w.cfg.Escalation.Interval,
+		w.approvalService.EscalateStaleApprovals, "⏫ Escalated %d stale approvals").Start(ctx)
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"expensio-backend/pkg/cache"
+)
+
+// periodicJob runs one pass at now and returns how many items it handled
+type periodicJob func(ctx context.Context, now time.Time) (int, error)
+
+// periodicRunner runs a job on every tick of its interval. A Redis lock held
+// for half the interval keeps several instances from running the same tick.
+type periodicRunner struct {
+	name     string // Worker name for the logs
+	lockKey  string
+	interval time.Duration
+	job      periodicJob
+	report   string // Logged with the count when a pass handled items
+}
+
+// newPeriodicRunner creates a runner for job
+func newPeriodicRunner(name, lockKey string, interval time.Duration, job periodicJob, report string) *periodicRunner {
+	return &periodicRunner{
+		name:     name,
+		lockKey:  lockKey,
+		interval: interval,
+		job:      job,
+		report:   report,
+	}
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (r *periodicRunner) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		log.Printf("⏰ %s started (every %s)", r.name, r.interval)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("🛑 %s stopped", r.name)
+				return
+			case now := <-ticker.C:
+				r.run(ctx, now)
+			}
+		}
+	}()
+}
+
+// run performs one pass unless another instance holds the lock
+func (r *periodicRunner) run(ctx context.Context, now time.Time) {
+	hostname, _ := os.Hostname()
+	acquired, err := cache.SetNX(r.lockKey, hostname, r.interval/2)
+	if err != nil {
+		log.Printf("⚠️  %s could not acquire lock: %v", r.name, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	handled, err := r.job(ctx, now)
+	if err != nil {
+		log.Printf("❌ %s failed: %v", r.name, err)
+		return
+	}
+	if handled > 0 {
+		log.Printf(r.report, handled)
+	}
+}
@@ -0,0 +1,69 @@
+package businesshours
+
+import (
+	"time"
+)
+
+// Calendar describes the working hours of a week: Monday to Friday between
+// StartHour and EndHour in Location
+type Calendar struct {
+	StartHour int
+	EndHour   int
+	Location  *time.Location
+}
+
+// NewCalendar creates a calendar, falling back to a 9-17 UTC working day
+// when the hours are out of range or the timezone is unknown
+func NewCalendar(startHour, endHour int, timezone string) *Calendar {
+	if startHour < 0 || endHour > 24 || startHour >= endHour {
+		startHour, endHour = 9, 17
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	return &Calendar{
+		StartHour: startHour,
+		EndHour:   endHour,
+		Location:  location,
+	}
+}
+
+// Elapsed returns the working time between from and to, skipping nights and weekends
+func (c *Calendar) Elapsed(from, to time.Time) time.Duration {
+	if !to.After(from) {
+		return 0
+	}
+
+	from = from.In(c.Location)
+	to = to.In(c.Location)
+
+	var elapsed time.Duration
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, c.Location)
+	for !day.After(to) {
+		if isWorkday(day) {
+			start := time.Date(day.Year(), day.Month(), day.Day(), c.StartHour, 0, 0, 0, c.Location)
+			end := time.Date(day.Year(), day.Month(), day.Day(), c.EndHour, 0, 0, 0, c.Location)
+			if from.After(start) {
+				start = from
+			}
+			if to.Before(end) {
+				end = to
+			}
+			if end.After(start) {
+				elapsed += end.Sub(start)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return elapsed
+}
+
+// isWorkday reports whether the day falls on Monday to Friday
+func isWorkday(day time.Time) bool {
+	weekday := day.Weekday()
+	return weekday != time.Saturday && weekday != time.Sunday
+}
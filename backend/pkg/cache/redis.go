@@ -121,6 +121,22 @@ func SetString(key string, value string, ttl time.Duration) error {
 	return Client.Set(ctx, key, value, ttl).Err()
 }
 
+// SetNX stores a string value only if the key does not exist yet and
+// reports whether it was stored
+func SetNX(key string, value string, ttl time.Duration) (bool, error) {
+	return Client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// Take deletes a key and reports whether it existed. Of concurrent callers
+// only one can take the same key.
+func Take(key string) (bool, error) {
+	deleted, err := Client.Del(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
 // GetString retrieves a string value from Redis
 func GetString(key string) (string, error) {
 	return Client.Get(ctx, key).Result()
@@ -0,0 +1,60 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"expensio-backend/internal/config"
+)
+
+// Cursor is the position after the last item of a page in a list ordered by
+// (created_at, _id)
+type Cursor struct {
+	Scope     string    `json:"s"` // List the cursor was issued for
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+	Asc       bool      `json:"asc,omitempty"`
+}
+
+// Encode signs the cursor into an opaque URL-safe token
+func Encode(c *Cursor, cfg *config.Config) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(encoded, cfg), nil
+}
+
+// Decode verifies the token's signature and that it was issued for scope
+func Decode(token, scope string, cfg *config.Config) (*Cursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(encoded, cfg))) {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil || c.Scope != scope {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &c, nil
+}
+
+// sign returns the URL-safe HMAC of the encoded payload
+func sign(encoded string, cfg *config.Config) string {
+	mac := hmac.New(sha256.New, []byte(cfg.Pagination.CursorSecret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
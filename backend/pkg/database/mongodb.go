@@ -8,6 +8,7 @@ import (
 
 	"expensio-backend/internal/config"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -120,6 +121,34 @@ func createIndexes(ctx context.Context) error {
 		{
 			Keys: map[string]interface{}{"company_id": 1, "status": 1, "created_at": -1},
 		},
+		{
+			Keys: map[string]interface{}{"reimbursement_batch_id": 1},
+		},
+		{
+			Keys: map[string]interface{}{"report_id": 1},
+		},
+		// List filters and sorting, see ExpenseFilter
+		{
+			Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "expense_date", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "category", Value: 1}, {Key: "expense_date", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "converted_amount", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "expense_date", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "description", Value: "text"}, {Key: "merchant", Value: "text"}},
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create expenses indexes: %w", err)
@@ -134,9 +163,16 @@ func createIndexes(ctx context.Context) error {
 		{
 			Keys: map[string]interface{}{"approver_id": 1, "status": 1},
 		},
+		// Keyset pages of an approver's pending approvals
+		{
+			Keys: bson.D{{Key: "approver_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
 		{
 			Keys: map[string]interface{}{"status": 1},
 		},
+		{
+			Keys: map[string]interface{}{"status": 1, "assigned_at": 1},
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create approvals indexes: %w", err)
@@ -151,6 +187,61 @@ func createIndexes(ctx context.Context) error {
 		return fmt.Errorf("failed to create approval_rules indexes: %w", err)
 	}
 
+	// Delegations collection indexes
+	delegationsCollection := GetCollection("delegations")
+	_, err = delegationsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: map[string]interface{}{"delegator_id": 1, "is_active": 1},
+		},
+		{
+			Keys: map[string]interface{}{"company_id": 1},
+		},
+		{
+			Keys: map[string]interface{}{"delegate_id": 1},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create delegations indexes: %w", err)
+	}
+
+	// Audit entries collection indexes, the unique sequence keeps each company's chain linear
+	auditEntriesCollection := GetCollection("audit_entries")
+	_, err = auditEntriesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "company_id", Value: 1}, {Key: "sequence", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "entity_id", Value: 1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create audit_entries indexes: %w", err)
+	}
+
+	// Reimbursement batches collection indexes
+	batchesCollection := GetCollection("reimbursement_batches")
+	_, err = batchesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reimbursement_batches indexes: %w", err)
+	}
+
+	// Expense reports collection indexes
+	reportsCollection := GetCollection("expense_reports")
+	_, err = reportsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "company_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create expense_reports indexes: %w", err)
+	}
+
 	log.Println("✅ Database indexes created successfully")
 	return nil
 }
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithTransaction runs fn in a MongoDB transaction. The writes fn makes with
+// the context it is given commit or abort together, and fn runs again when the
+// transaction hits a transient error, so it must not keep state between runs.
+// Transactions need MongoDB to run as a replica set.
+func WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := Client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	})
+	return err
+}
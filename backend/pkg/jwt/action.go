@@ -0,0 +1,67 @@
+package jwt
+
+import (
+	"fmt"
+	"time"
+
+	"expensio-backend/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ActionClaims authorize a single approval decision without a session
+type ActionClaims struct {
+	ApprovalID string `json:"approval_id"`
+	ApproverID string `json:"approver_id"`
+	Action     string `json:"action"`     // "approve" or "reject"
+	TokenType  string `json:"token_type"` // Always "action"
+	jwt.RegisteredClaims
+}
+
+// GenerateActionToken generates a signed action token for the approver's
+// decision on an approval. The token ID is what makes it single-use.
+func GenerateActionToken(approvalID, approverID, action string, cfg *config.Config) (string, *ActionClaims, error) {
+	claims := &ActionClaims{
+		ApprovalID: approvalID,
+		ApproverID: approverID,
+		Action:     action,
+		TokenType:  "action",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.ActionLink.Expiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(cfg.ActionLink.Secret))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, claims, nil
+}
+
+// ValidateActionToken validates the signature and expiry of an action token
+func ValidateActionToken(tokenString string, cfg *config.Config) (*ActionClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ActionClaims{}, func(token *jwt.Token) (interface{}, error) {
+		// Validate signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(cfg.ActionLink.Secret), nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*ActionClaims)
+	if !ok || !token.Valid || claims.TokenType != "action" {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+
+	return claims, nil
+}
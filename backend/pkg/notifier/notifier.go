@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"expensio-backend/internal/config"
+)
+
+// Message is a notification to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string // Plain text
+}
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewNotifier returns the SMTP notifier when a mail server is configured and
+// the log notifier otherwise
+func NewNotifier(cfg *config.Config) Notifier {
+	if cfg.SMTP.Host == "" {
+		return NewLogNotifier()
+	}
+	return NewSMTPNotifier(cfg)
+}
+
+// LogNotifier prints notifications instead of delivering them, for development
+type LogNotifier struct{}
+
+// NewLogNotifier creates a new log notifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Send(ctx context.Context, msg *Message) error {
+	fmt.Printf("📨 Notification to %s: %s\n%s\n", msg.To, msg.Subject, msg.Body)
+	return nil
+}
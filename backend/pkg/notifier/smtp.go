@@ -0,0 +1,113 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"expensio-backend/internal/config"
+)
+
+// smtpDialTimeout bounds how long connecting to the mail server may take
+const smtpDialTimeout = 10 * time.Second
+
+// SMTPNotifier mails notifications through an SMTP server. It upgrades to TLS
+// when the server offers STARTTLS and authenticates when credentials are set,
+// so a local fake server such as MailHog or Mailpit works without either.
+type SMTPNotifier struct {
+	host     string
+	addr     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPNotifier creates a new SMTP notifier
+func NewSMTPNotifier(cfg *config.Config) *SMTPNotifier {
+	return &SMTPNotifier{
+		host:     cfg.SMTP.Host,
+		addr:     net.JoinHostPort(cfg.SMTP.Host, cfg.SMTP.Port),
+		username: cfg.SMTP.Username,
+		password: cfg.SMTP.Password,
+		from:     cfg.SMTP.From,
+	}
+}
+
+func (n *SMTPNotifier) Send(ctx context.Context, msg *Message) error {
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to mail server: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if n.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.username, n.password, n.host)); err != nil {
+			return fmt.Errorf("failed to authenticate with mail server: %w", err)
+		}
+	}
+
+	if err := client.Mail(envelopeAddress(n.from)); err != nil {
+		return fmt.Errorf("mail server refused sender: %w", err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("mail server refused recipient: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if _, err := writer.Write(n.compose(msg)); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// compose builds the RFC 5322 message with CRLF line endings
+func (n *SMTPNotifier) compose(msg *Message) []byte {
+	headers := []string{
+		"From: " + n.from,
+		"To: " + msg.To,
+		"Subject: " + msg.Subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	body = strings.ReplaceAll(body, "\n", "\r\n")
+
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body + "\r\n")
+}
+
+// envelopeAddress returns the bare address of "Name <address>"
+func envelopeAddress(from string) string {
+	if start := strings.LastIndex(from, "<"); start >= 0 {
+		if end := strings.LastIndex(from, ">"); end > start {
+			return from[start+1 : end]
+		}
+	}
+	return strings.TrimSpace(from)
+}
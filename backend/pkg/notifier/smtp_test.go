@@ -0,0 +1,156 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"expensio-backend/internal/config"
+)
+
+// fakeSMTPSession is what the fake server received in one session
+type fakeSMTPSession struct {
+	commands []string
+	data     string
+}
+
+// startFakeSMTPServer accepts one session on a local port, answers like a
+// plain mail server without STARTTLS or AUTH and reports what it received
+func startFakeSMTPServer(t *testing.T) (string, <-chan fakeSMTPSession) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	sessions := make(chan fakeSMTPSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var session fakeSMTPSession
+		defer func() { sessions <- session }()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) {
+			conn.Write([]byte(line + "\r\n"))
+		}
+
+		reply("220 localhost fake ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			session.commands = append(session.commands, line)
+
+			switch verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); verb {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "MAIL", "RCPT":
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				session.data = data.String()
+				reply("250 OK: queued")
+			case "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), sessions
+}
+
+func TestSMTPNotifierSend(t *testing.T) {
+	addr, sessions := startFakeSMTPServer(t)
+	host, port, _ := net.SplitHostPort(addr)
+
+	n := NewSMTPNotifier(&config.Config{SMTP: config.SMTPConfig{
+		Host: host,
+		Port: port,
+		From: "Expensio <no-reply@expensio.local>",
+	}})
+
+	err := n.Send(context.Background(), &Message{
+		To:      "approver@example.com",
+		Subject: "3 expenses waiting for your approval",
+		Body:    "Hello Ada,\n\nYou have 3 expenses waiting.\n",
+	})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	session := <-sessions
+
+	var mailFrom, rcptTo string
+	for _, command := range session.commands {
+		switch {
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			mailFrom = command
+		case strings.HasPrefix(command, "RCPT TO:"):
+			rcptTo = command
+		}
+	}
+	if !strings.HasPrefix(mailFrom, "MAIL FROM:<no-reply@expensio.local>") {
+		t.Errorf("want the bare sender address in the envelope, got %q", mailFrom)
+	}
+	if rcptTo != "RCPT TO:<approver@example.com>" {
+		t.Errorf("want the recipient in the envelope, got %q", rcptTo)
+	}
+	if last := session.commands[len(session.commands)-1]; last != "QUIT" {
+		t.Errorf("want the session to end with QUIT, got %q", last)
+	}
+
+	headers, body, ok := strings.Cut(session.data, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("message has no header/body separator: %q", session.data)
+	}
+	for _, header := range []string{
+		"From: Expensio <no-reply@expensio.local>",
+		"To: approver@example.com",
+		"Subject: 3 expenses waiting for your approval",
+		"Content-Type: text/plain; charset=UTF-8",
+	} {
+		if !strings.Contains(headers+"\r\n", header+"\r\n") {
+			t.Errorf("header %q missing from %q", header, headers)
+		}
+	}
+	if want := "Hello Ada,\r\n\r\nYou have 3 expenses waiting.\r\n\r\n"; body != want {
+		t.Errorf("want body with CRLF line endings %q, got %q", want, body)
+	}
+}
+
+func TestEnvelopeAddress(t *testing.T) {
+	tests := map[string]string{
+		"Expensio <no-reply@expensio.local>": "no-reply@expensio.local",
+		"  no-reply@expensio.local ":         "no-reply@expensio.local",
+	}
+	for from, want := range tests {
+		if got := envelopeAddress(from); got != want {
+			t.Errorf("envelopeAddress(%q) = %q, want %q", from, got, want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 )
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
@@ -80,6 +81,18 @@ func ValidateAmount(amount float64) error {
 	return nil
 }
 
+// ValidateExpenseDate validates that the expense date is set and not in the
+// future. A day of slack covers clients in time zones ahead of the server.
+func ValidateExpenseDate(date time.Time) error {
+	if date.IsZero() {
+		return fmt.Errorf("expense date is required")
+	}
+	if date.After(time.Now().Add(24 * time.Hour)) {
+		return fmt.Errorf("expense date cannot be in the future")
+	}
+	return nil
+}
+
 // ValidateCategory validates expense category
 func ValidateCategory(category string) error {
 	validCategories := []string{"travel", "meals", "accommodation", "transport", "supplies", "other"}
@@ -94,6 +107,40 @@ func ValidateCategory(category string) error {
 	return fmt.Errorf("invalid category: must be one of %v", validCategories)
 }
 
+// ValidateExpenseStatus validates expense status
+func ValidateExpenseStatus(status string) error {
+	validStatuses := []string{"draft", "pending", "approved", "rejected", "changes_requested", "withdrawn", "scheduled", "paid"}
+
+	for _, validStatus := range validStatuses {
+		if status == validStatus {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid status: must be one of %v", validStatuses)
+}
+
+// ValidateApprovalRuleType validates approval rule type
+func ValidateApprovalRuleType(ruleType string) error {
+	validTypes := []string{"sequential", "percentage", "specific_approver", "hybrid", "manager_chain"}
+
+	for _, validType := range validTypes {
+		if ruleType == validType {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid rule type: must be one of %v", validTypes)
+}
+
+// ValidatePercentage validates a percentage value
+func ValidatePercentage(percentage float64, fieldName string) error {
+	if percentage <= 0 || percentage > 100 {
+		return fmt.Errorf("%s must be greater than 0 and at most 100", fieldName)
+	}
+	return nil
+}
+
 // ValidateDescription validates description length
 func ValidateDescription(description string) error {
 	if description == "" {